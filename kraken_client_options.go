@@ -0,0 +1,81 @@
+package kraken
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	KrakenSpotBaseURL        = "https://api.kraken.com/0"
+	KrakenFuturesBaseURL     = "https://futures.kraken.com"
+	KrakenFuturesDemoBaseURL = "https://demo-futures.kraken.com"
+)
+
+// ClientOption configures a KrakenSpotHttpClient or KrakenFuturesHttpClient.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	httpClient *http.Client
+	baseURL    string
+	userAgent  string
+	timeout    time.Duration
+	retry      RetryPolicy
+	limiter    RateLimiter
+
+	// spot private endpoints only
+	apiKey    string
+	apiSecret string
+	otp       func() (string, error)
+	nonce     NonceGenerator
+}
+
+// WithHTTPClient sets the http.Client used to issue requests. This is the
+// place to configure transports, proxies and connection pooling.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		if client != nil {
+			o.httpClient = client
+		}
+	}
+}
+
+// WithBaseURL overrides the API base URL, e.g. KrakenFuturesDemoBaseURL or
+// the URL of an httptest.Server.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(o *clientOptions) {
+		o.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets the overall timeout of each HTTP request. It is applied to
+// a copy of the configured http.Client so a shared client is not modified.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+func newClientOptions(baseURL string, opts []ClientOption) clientOptions {
+	o := clientOptions{
+		httpClient: http.DefaultClient,
+		baseURL:    baseURL,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.timeout > 0 {
+		client := *o.httpClient
+		client.Timeout = o.timeout
+		o.httpClient = &client
+	}
+	return o
+}
@@ -0,0 +1,286 @@
+package kraken
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number used for prices, quantities and other
+// amounts. Kraken sends these either as strings ("30306.10000") or as JSON
+// numbers; both are decoded without going through float64, and the number of
+// decimal places is kept so String returns the value as Kraken sent it.
+//
+// The zero value is 0. Decimals are immutable and safe to copy.
+type Decimal struct {
+	// value * 10^-scale, a nil value is zero
+	value *big.Int
+	scale int32
+}
+
+var bigTen = big.NewInt(10)
+
+// NewDecimal returns value * 10^-scale, e.g. NewDecimal(12345, 2) is 123.45.
+func NewDecimal(value int64, scale int32) Decimal {
+	return Decimal{value: big.NewInt(value), scale: scale}
+}
+
+func NewDecimalFromInt(value int64) Decimal {
+	return NewDecimal(value, 0)
+}
+
+// NewDecimalFromFloat returns the shortest decimal that converts back to f.
+func NewDecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("kraken: cannot convert %v to a decimal", f)
+	}
+	return ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// maxDecimalExponent bounds the exponent ParseDecimal accepts, so that a short
+// input such as "1e50000000" cannot expand into a huge number.
+const maxDecimalExponent = 1000
+
+// ParseDecimal parses a decimal number such as "-30306.10000" or "1.5e-05".
+// Exponents are limited to ±1000.
+func ParseDecimal(s string) (Decimal, error) {
+	text := s
+	exponent := int64(0)
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(text[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("kraken: invalid decimal %q", s)
+		}
+		if e < -maxDecimalExponent || e > maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("kraken: decimal %q out of range", s)
+		}
+		exponent = e
+		text = text[:i]
+	}
+
+	digits := text
+	if len(digits) > 0 && (digits[0] == '+' || digits[0] == '-') {
+		digits = digits[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if len(intPart)+len(fracPart) == 0 || strings.ContainsAny(intPart+fracPart, "+-") {
+		return Decimal{}, fmt.Errorf("kraken: invalid decimal %q", s)
+	}
+
+	value, ok := new(big.Int).SetString(strings.Replace(text, ".", "", 1), 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("kraken: invalid decimal %q", s)
+	}
+
+	scale := int64(len(fracPart)) - exponent
+	if scale < math.MinInt32 || scale > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("kraken: decimal %q out of range", s)
+	}
+	if scale < 0 {
+		value.Mul(value, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{value: value, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s is not a decimal. It is
+// meant for constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) bigValue() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+// rescale returns the value of d with scale digits after the point, scale >= d.scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	value := new(big.Int).Set(d.bigValue())
+	if scale > d.scale {
+		value.Mul(value, pow10(scale-d.scale))
+	}
+	return value
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) Add(e Decimal) Decimal {
+	scale := max(d.scale, e.scale)
+	return Decimal{value: new(big.Int).Add(d.rescale(scale), e.rescale(scale)), scale: scale}
+}
+
+func (d Decimal) Sub(e Decimal) Decimal {
+	scale := max(d.scale, e.scale)
+	return Decimal{value: new(big.Int).Sub(d.rescale(scale), e.rescale(scale)), scale: scale}
+}
+
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{value: new(big.Int).Mul(d.bigValue(), e.bigValue()), scale: d.scale + e.scale}
+}
+
+// Div returns d / e rounded half away from zero to places decimal places.
+// It panics if e is zero.
+func (d Decimal) Div(e Decimal, places int32) Decimal {
+	if e.IsZero() {
+		panic("kraken: decimal division by zero")
+	}
+	// d/e = (d.value * 10^(places + 1 + e.scale - d.scale)) / e.value * 10^-(places+1)
+	shift := places + 1 + e.scale - d.scale
+	numerator := new(big.Int).Set(d.bigValue())
+	denominator := new(big.Int).Set(e.bigValue())
+	if shift >= 0 {
+		numerator.Mul(numerator, pow10(shift))
+	} else {
+		denominator.Mul(denominator, pow10(-shift))
+	}
+	quotient := Decimal{value: new(big.Int).Quo(numerator, denominator), scale: places + 1}
+	return quotient.Round(places)
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.bigValue()), scale: d.scale}
+}
+
+func (d Decimal) Abs() Decimal {
+	return Decimal{value: new(big.Int).Abs(d.bigValue()), scale: d.scale}
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.bigValue().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp returns -1 if d < e, 0 if d == e and +1 if d > e.
+func (d Decimal) Cmp(e Decimal) int {
+	scale := max(d.scale, e.scale)
+	return d.rescale(scale).Cmp(e.rescale(scale))
+}
+
+// Equal reports whether d and e are the same number, whatever their scales.
+func (d Decimal) Equal(e Decimal) bool {
+	return d.Cmp(e) == 0
+}
+
+func (d Decimal) LessThan(e Decimal) bool {
+	return d.Cmp(e) < 0
+}
+
+func (d Decimal) GreaterThan(e Decimal) bool {
+	return d.Cmp(e) > 0
+}
+
+// Round rounds d half away from zero to places decimal places.
+func (d Decimal) Round(places int32) Decimal {
+	if places >= d.scale {
+		return d
+	}
+	divisor := pow10(d.scale - places)
+	quotient, remainder := new(big.Int).QuoRem(d.bigValue(), divisor, new(big.Int))
+	remainder.Abs(remainder).Lsh(remainder, 1)
+	if remainder.Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(d.Sign())))
+	}
+	return Decimal{value: quotient, scale: places}
+}
+
+// Truncate drops the digits of d beyond places decimal places, rounding
+// towards zero.
+func (d Decimal) Truncate(places int32) Decimal {
+	if places >= d.scale {
+		return d
+	}
+	return Decimal{value: new(big.Int).Quo(d.bigValue(), pow10(d.scale-places)), scale: places}
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d in plain decimal notation with Scale digits after the point.
+func (d Decimal) String() string {
+	if d.scale <= 0 {
+		return d.rescale(0).String()
+	}
+
+	digits := new(big.Int).Abs(d.bigValue()).String()
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// MarshalJSON encodes d as a string, the form Kraken uses for amounts.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts a JSON number, a string holding a number, an empty
+// string or null. The last two decode to zero.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		if len(text) == 0 {
+			*d = Decimal{}
+			return nil
+		}
+	}
+
+	parsed, err := ParseDecimal(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
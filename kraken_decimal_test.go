@@ -0,0 +1,200 @@
+package kraken
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for _, tt := range []struct {
+		in    string
+		want  string
+		scale int32
+	}{
+		{"0", "0", 0},
+		{"30306.10000", "30306.10000", 5},
+		{"-30306.1", "-30306.1", 1},
+		{"+1.5", "1.5", 1},
+		{".5", "0.5", 1},
+		{"5.", "5", 0},
+		{"-0.000001", "-0.000001", 6},
+		{"1.5e-05", "0.000015", 6},
+		{"1.5E-05", "0.000015", 6},
+		{"-2.5e3", "-2500", 0},
+		{"12e+2", "1200", 0},
+		{"123.456e1", "1234.56", 2},
+		{"1e1000", "1" + strings.Repeat("0", 1000), 0},
+		{"123456789012345678901234567890.123456789", "123456789012345678901234567890.123456789", 9},
+	} {
+		d, err := ParseDecimal(tt.in)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q) error = %v", tt.in, err)
+			continue
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+		if d.Scale() != tt.scale {
+			t.Errorf("ParseDecimal(%q).Scale() = %d, want %d", tt.in, d.Scale(), tt.scale)
+		}
+	}
+}
+
+func TestParseDecimalErrors(t *testing.T) {
+	for _, in := range []string{
+		"", "-", ".", "abc", "1.2.3", "1-2", "--1", "1e", "1ex", "0x10", "1,5", " 1",
+		"1e1001", "1e-1001", "1e50000000", "1e-50000000", "1e99999999999",
+	} {
+		if d, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%q) = %s, want an error", in, d)
+		}
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	for _, tt := range []struct {
+		d    Decimal
+		want string
+	}{
+		{Decimal{}, "0"},
+		{NewDecimal(12345, 2), "123.45"},
+		{NewDecimal(-12345, 2), "-123.45"},
+		{NewDecimal(5, 3), "0.005"},
+		{NewDecimal(-5, 3), "-0.005"},
+		{NewDecimal(0, 2), "0.00"},
+		{NewDecimal(12, 0), "12"},
+		{NewDecimal(12, -3), "12000"},
+		{NewDecimal(-12, -2), "-1200"},
+	} {
+		if got := tt.d.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := MustParseDecimal("1.25"), MustParseDecimal("-0.125")
+	for _, tt := range []struct {
+		name string
+		got  Decimal
+		want string
+	}{
+		{"Add", a.Add(b), "1.125"},
+		{"Sub", a.Sub(b), "1.375"},
+		{"Mul", a.Mul(b), "-0.15625"},
+		{"Neg", b.Neg(), "0.125"},
+		{"Abs", b.Abs(), "0.125"},
+		{"Add scale <= 0", NewDecimal(12, -2).Add(MustParseDecimal("0.5")), "1200.5"},
+		{"Mul scale <= 0", NewDecimal(12, -2).Mul(NewDecimal(3, 0)), "3600"},
+	} {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || !a.Equal(MustParseDecimal("1.2500")) {
+		t.Errorf("Cmp/Equal disagree with 1.25 > -0.125 == -0.1250")
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	for _, tt := range []struct {
+		in     string
+		places int32
+		want   string
+	}{
+		{"1.2345", 2, "1.23"},
+		{"1.235", 2, "1.24"},
+		{"1.245", 2, "1.25"},
+		{"-1.235", 2, "-1.24"},
+		{"-1.234", 2, "-1.23"},
+		{"0.005", 2, "0.01"},
+		{"-0.005", 2, "-0.01"},
+		{"0.004", 2, "0.00"},
+		{"1.5", 0, "2"},
+		{"-1.5", 0, "-2"},
+		{"1.2", 4, "1.2"},
+		{"1250", -2, "1300"},
+		{"-1249", -2, "-1200"},
+	} {
+		if got := MustParseDecimal(tt.in).Round(tt.places).String(); got != tt.want {
+			t.Errorf("%s.Round(%d) = %s, want %s", tt.in, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalTruncate(t *testing.T) {
+	for _, tt := range []struct {
+		in     string
+		places int32
+		want   string
+	}{
+		{"1.2399", 2, "1.23"},
+		{"-1.2399", 2, "-1.23"},
+		{"0.009", 2, "0.00"},
+		{"1.9", 0, "1"},
+		{"-1.9", 0, "-1"},
+		{"1.2", 4, "1.2"},
+		{"1299", -2, "1200"},
+	} {
+		if got := MustParseDecimal(tt.in).Truncate(tt.places).String(); got != tt.want {
+			t.Errorf("%s.Truncate(%d) = %s, want %s", tt.in, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalDiv(t *testing.T) {
+	for _, tt := range []struct {
+		d, e   string
+		places int32
+		want   string
+	}{
+		{"1", "3", 4, "0.3333"},
+		{"2", "3", 4, "0.6667"},
+		{"-2", "3", 4, "-0.6667"},
+		{"2", "-3", 2, "-0.67"},
+		{"-1", "-8", 3, "0.125"},
+		{"1", "8", 2, "0.13"},
+		{"10", "4", 0, "3"},
+		{"0.5", "0.25", 1, "2.0"},
+		{"1.2e3", "0.001", 0, "1200000"},
+		{"7", "2", -1, "0"},
+		{"150", "1", -2, "200"},
+	} {
+		got := MustParseDecimal(tt.d).Div(MustParseDecimal(tt.e), tt.places).String()
+		if got != tt.want {
+			t.Errorf("%s.Div(%s, %d) = %s, want %s", tt.d, tt.e, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalDivByZeroPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Div by zero did not panic")
+		}
+	}()
+	NewDecimalFromInt(1).Div(Decimal{}, 2)
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var v struct {
+		A, B, C, D Decimal
+	}
+	if err := json.Unmarshal([]byte(`{"A":"30306.10000","B":1.5e-5,"C":"","D":null}`), &v); err != nil {
+		t.Fatalf("Unmarshal error = %v", err)
+	}
+	if v.A.String() != "30306.10000" || v.B.String() != "0.000015" || !v.C.IsZero() || !v.D.IsZero() {
+		t.Errorf("decoded %s %s %s %s", v.A, v.B, v.C, v.D)
+	}
+
+	data, err := json.Marshal(v.A)
+	if err != nil || string(data) != `"30306.10000"` {
+		t.Errorf("Marshal = %s, %v, want \"30306.10000\"", data, err)
+	}
+
+	if err := json.Unmarshal([]byte(`"1e50000000"`), &v.A); err == nil {
+		t.Error("Unmarshal of an out of range exponent succeeded")
+	}
+}
@@ -0,0 +1,113 @@
+package kraken
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// positionalDecoder decodes the positional arrays Kraken uses for trades, price
+// levels, candles and spreads, e.g. ["30306.1", "0.5", 1688671969.123, "b"].
+// The first error is kept and later reads become no-ops, so a caller can read
+// every field and check err once.
+type positionalDecoder struct {
+	typeName string
+	fields   []json.RawMessage
+	err      error
+}
+
+func newPositionalDecoder(typeName string, data []byte, minFields int) *positionalDecoder {
+	d := &positionalDecoder{typeName: typeName}
+	if err := json.Unmarshal(data, &d.fields); err != nil {
+		d.err = fmt.Errorf("kraken: decoding %s: %w", typeName, err)
+		return d
+	}
+	if len(d.fields) < minFields {
+		d.err = fmt.Errorf("kraken: decoding %s: expected at least %d fields, got %d", typeName, minFields, len(d.fields))
+	}
+	return d
+}
+
+func (d *positionalDecoder) fail(i int, name string, err error) {
+	d.err = fmt.Errorf("kraken: decoding %s: field %d (%s): %w", d.typeName, i, name, err)
+}
+
+// check records err, if any, as the error of field i.
+func (d *positionalDecoder) check(i int, name string, err error) {
+	if d.err == nil && err != nil {
+		d.fail(i, name, err)
+	}
+}
+
+// number returns the text of a numeric field, which Kraken sends either as a
+// JSON number or as a string holding a number.
+func (d *positionalDecoder) number(i int, name string) string {
+	if d.err != nil {
+		return ""
+	}
+
+	raw := d.fields[i]
+	var s string
+	if len(raw) > 0 && raw[0] == '"' {
+		if err := json.Unmarshal(raw, &s); err != nil {
+			d.fail(i, name, err)
+			return ""
+		}
+	} else {
+		var n json.Number
+		if err := json.Unmarshal(raw, &n); err != nil {
+			d.fail(i, name, err)
+			return ""
+		}
+		s = n.String()
+	}
+	return s
+}
+
+func (d *positionalDecoder) decimal(i int, name string) Decimal {
+	s := d.number(i, name)
+	if d.err != nil {
+		return Decimal{}
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		d.fail(i, name, err)
+	}
+	return v
+}
+
+// time decodes a field holding Unix seconds.
+func (d *positionalDecoder) time(i int, name string) KrakenTime {
+	s := d.number(i, name)
+	if d.err != nil {
+		return KrakenTime{}
+	}
+	v, err := parseUnixSeconds(s)
+	if err != nil {
+		d.fail(i, name, err)
+	}
+	return KrakenTime{v}
+}
+
+func (d *positionalDecoder) int64(i int, name string) int64 {
+	s := d.number(i, name)
+	if d.err != nil {
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		d.fail(i, name, err)
+	}
+	return v
+}
+
+func (d *positionalDecoder) string(i int, name string) string {
+	if d.err != nil {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(d.fields[i], &s); err != nil {
+		d.fail(i, name, err)
+	}
+	return s
+}
@@ -0,0 +1,441 @@
+package kraken
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// KrakenSide is the side of an order or trade. ParseKrakenSide accepts both
+// the spot short forms "b"/"s" and the long forms "buy"/"sell" and rejects
+// anything else. Decoding from JSON also maps the short forms but keeps any
+// other string, so an unexpected side does not break decoding of a whole
+// response; use Valid to tell known values apart. Sides encode to the long
+// form Kraken expects in requests.
+type KrakenSide string
+
+const (
+	KrakenSideBuy  KrakenSide = "buy"
+	KrakenSideSell KrakenSide = "sell"
+)
+
+func ParseKrakenSide(s string) (KrakenSide, error) {
+	switch s {
+	case "b", "buy":
+		return KrakenSideBuy, nil
+	case "s", "sell":
+		return KrakenSideSell, nil
+	}
+	return "", fmt.Errorf("kraken: invalid side %q", s)
+}
+
+func (s KrakenSide) String() string {
+	return string(s)
+}
+
+func (s KrakenSide) Valid() bool {
+	return s == KrakenSideBuy || s == KrakenSideSell
+}
+
+func (s *KrakenSide) UnmarshalJSON(data []byte) error {
+	return unmarshalLenientEnum(data, s, ParseKrakenSide)
+}
+
+// KrakenOrderType is the type of an order. ParseKrakenOrderType accepts the
+// spot trade short forms "m" (market) and "l" (limit) as well as the long
+// forms and rejects anything else. Decoding from JSON also maps the short
+// forms but keeps any other string, so an order type Kraken adds later does
+// not break decoding of orders, fills or positions; use Valid to tell known
+// values apart.
+type KrakenOrderType string
+
+const (
+	KrakenOrderTypeMarket            KrakenOrderType = "market"
+	KrakenOrderTypeLimit             KrakenOrderType = "limit"
+	KrakenOrderTypeIceberg           KrakenOrderType = "iceberg"
+	KrakenOrderTypeStopLoss          KrakenOrderType = "stop-loss"
+	KrakenOrderTypeTakeProfit        KrakenOrderType = "take-profit"
+	KrakenOrderTypeStopLossLimit     KrakenOrderType = "stop-loss-limit"
+	KrakenOrderTypeTakeProfitLimit   KrakenOrderType = "take-profit-limit"
+	KrakenOrderTypeTrailingStop      KrakenOrderType = "trailing-stop"
+	KrakenOrderTypeTrailingStopLimit KrakenOrderType = "trailing-stop-limit"
+	KrakenOrderTypeSettlePosition    KrakenOrderType = "settle-position"
+)
+
+func ParseKrakenOrderType(s string) (KrakenOrderType, error) {
+	switch s {
+	case "m":
+		return KrakenOrderTypeMarket, nil
+	case "l":
+		return KrakenOrderTypeLimit, nil
+	}
+	if t := KrakenOrderType(s); t.Valid() {
+		return t, nil
+	}
+	return "", fmt.Errorf("kraken: invalid order type %q", s)
+}
+
+func (t KrakenOrderType) String() string {
+	return string(t)
+}
+
+func (t KrakenOrderType) Valid() bool {
+	switch t {
+	case KrakenOrderTypeMarket, KrakenOrderTypeLimit, KrakenOrderTypeIceberg,
+		KrakenOrderTypeStopLoss, KrakenOrderTypeTakeProfit,
+		KrakenOrderTypeStopLossLimit, KrakenOrderTypeTakeProfitLimit,
+		KrakenOrderTypeTrailingStop, KrakenOrderTypeTrailingStopLimit,
+		KrakenOrderTypeSettlePosition:
+		return true
+	}
+	return false
+}
+
+func (t *KrakenOrderType) UnmarshalJSON(data []byte) error {
+	return unmarshalLenientEnum(data, t, ParseKrakenOrderType)
+}
+
+// unmarshalLenientEnum decodes a string with parse, keeping the string as it
+// is when parse rejects it.
+func unmarshalLenientEnum[T ~string](data []byte, v *T, parse func(string) (T, error)) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if parsed, err := parse(s); err == nil {
+		*v = parsed
+		return nil
+	}
+	*v = T(s)
+	return nil
+}
+
+// The status and class enums below decode any string so that a value Kraken
+// adds later does not break decoding of the whole response; use Valid to tell
+// known values apart.
+
+// KrakenSpotSystemState is the state of the spot exchange.
+type KrakenSpotSystemState string
+
+const (
+	KrakenSpotSystemOnline      KrakenSpotSystemState = "online"
+	KrakenSpotSystemMaintenance KrakenSpotSystemState = "maintenance"
+	KrakenSpotSystemCancelOnly  KrakenSpotSystemState = "cancel_only"
+	KrakenSpotSystemPostOnly    KrakenSpotSystemState = "post_only"
+)
+
+func (s KrakenSpotSystemState) String() string {
+	return string(s)
+}
+
+func (s KrakenSpotSystemState) Valid() bool {
+	switch s {
+	case KrakenSpotSystemOnline, KrakenSpotSystemMaintenance, KrakenSpotSystemCancelOnly, KrakenSpotSystemPostOnly:
+		return true
+	}
+	return false
+}
+
+// KrakenSpotPairStatus is the trading status of an asset pair.
+type KrakenSpotPairStatus string
+
+const (
+	KrakenSpotPairOnline     KrakenSpotPairStatus = "online"
+	KrakenSpotPairCancelOnly KrakenSpotPairStatus = "cancel_only"
+	KrakenSpotPairPostOnly   KrakenSpotPairStatus = "post_only"
+	KrakenSpotPairLimitOnly  KrakenSpotPairStatus = "limit_only"
+	KrakenSpotPairReduceOnly KrakenSpotPairStatus = "reduce_only"
+)
+
+func (s KrakenSpotPairStatus) String() string {
+	return string(s)
+}
+
+func (s KrakenSpotPairStatus) Valid() bool {
+	switch s {
+	case KrakenSpotPairOnline, KrakenSpotPairCancelOnly, KrakenSpotPairPostOnly, KrakenSpotPairLimitOnly, KrakenSpotPairReduceOnly:
+		return true
+	}
+	return false
+}
+
+// KrakenSpotAssetStatus is the funding status of an asset.
+type KrakenSpotAssetStatus string
+
+const (
+	KrakenSpotAssetEnabled                    KrakenSpotAssetStatus = "enabled"
+	KrakenSpotAssetDepositOnly                KrakenSpotAssetStatus = "deposit_only"
+	KrakenSpotAssetWithdrawalOnly             KrakenSpotAssetStatus = "withdrawal_only"
+	KrakenSpotAssetFundingTemporarilyDisabled KrakenSpotAssetStatus = "funding_temporarily_disabled"
+)
+
+func (s KrakenSpotAssetStatus) String() string {
+	return string(s)
+}
+
+func (s KrakenSpotAssetStatus) Valid() bool {
+	switch s {
+	case KrakenSpotAssetEnabled, KrakenSpotAssetDepositOnly, KrakenSpotAssetWithdrawalOnly, KrakenSpotAssetFundingTemporarilyDisabled:
+		return true
+	}
+	return false
+}
+
+// KrakenAssetClass is the class of an asset.
+type KrakenAssetClass string
+
+const (
+	KrakenAssetClassCurrency       KrakenAssetClass = "currency"
+	KrakenAssetClassTokenizedAsset KrakenAssetClass = "tokenized_asset"
+)
+
+func (c KrakenAssetClass) String() string {
+	return string(c)
+}
+
+func (c KrakenAssetClass) Valid() bool {
+	return c == KrakenAssetClassCurrency || c == KrakenAssetClassTokenizedAsset
+}
+
+// KrakenFuturesTradeType is the kind of a futures public trade.
+type KrakenFuturesTradeType string
+
+const (
+	KrakenFuturesTradeFill        KrakenFuturesTradeType = "fill"
+	KrakenFuturesTradeLiquidation KrakenFuturesTradeType = "liquidation"
+	KrakenFuturesTradeAssignment  KrakenFuturesTradeType = "assignment"
+	KrakenFuturesTradeTermination KrakenFuturesTradeType = "termination"
+	KrakenFuturesTradeBlock       KrakenFuturesTradeType = "block"
+)
+
+func (t KrakenFuturesTradeType) String() string {
+	return string(t)
+}
+
+func (t KrakenFuturesTradeType) Valid() bool {
+	switch t {
+	case KrakenFuturesTradeFill, KrakenFuturesTradeLiquidation, KrakenFuturesTradeAssignment,
+		KrakenFuturesTradeTermination, KrakenFuturesTradeBlock:
+		return true
+	}
+	return false
+}
+
+// KrakenSpotOrderFlag is an order flag of a spot order.
+type KrakenSpotOrderFlag string
+
+const (
+	// KrakenSpotOrderFlagPostOnly rejects the order if it would take liquidity.
+	KrakenSpotOrderFlagPostOnly KrakenSpotOrderFlag = "post"
+	// KrakenSpotOrderFlagFeeInBase charges the fee in the base currency.
+	KrakenSpotOrderFlagFeeInBase KrakenSpotOrderFlag = "fcib"
+	// KrakenSpotOrderFlagFeeInQuote charges the fee in the quote currency.
+	KrakenSpotOrderFlagFeeInQuote KrakenSpotOrderFlag = "fciq"
+	// KrakenSpotOrderFlagNoMarketPriceProtection disables market price protection.
+	KrakenSpotOrderFlagNoMarketPriceProtection KrakenSpotOrderFlag = "nompp"
+	// KrakenSpotOrderFlagVolumeInQuote expresses the volume of a market buy in
+	// the quote currency.
+	KrakenSpotOrderFlagVolumeInQuote KrakenSpotOrderFlag = "viqc"
+)
+
+func (f KrakenSpotOrderFlag) String() string {
+	return string(f)
+}
+
+func (f KrakenSpotOrderFlag) Valid() bool {
+	switch f {
+	case KrakenSpotOrderFlagPostOnly, KrakenSpotOrderFlagFeeInBase, KrakenSpotOrderFlagFeeInQuote,
+		KrakenSpotOrderFlagNoMarketPriceProtection, KrakenSpotOrderFlagVolumeInQuote:
+		return true
+	}
+	return false
+}
+
+// KrakenSpotTimeInForce is how long a spot order stays on the book.
+type KrakenSpotTimeInForce string
+
+const (
+	KrakenSpotTimeInForceGTC KrakenSpotTimeInForce = "GTC"
+	KrakenSpotTimeInForceIOC KrakenSpotTimeInForce = "IOC"
+	KrakenSpotTimeInForceGTD KrakenSpotTimeInForce = "GTD"
+)
+
+func (t KrakenSpotTimeInForce) String() string {
+	return string(t)
+}
+
+func (t KrakenSpotTimeInForce) Valid() bool {
+	return t == KrakenSpotTimeInForceGTC || t == KrakenSpotTimeInForceIOC || t == KrakenSpotTimeInForceGTD
+}
+
+// KrakenSpotOrderTrigger is the price that triggers a conditional spot order.
+type KrakenSpotOrderTrigger string
+
+const (
+	KrakenSpotOrderTriggerLast  KrakenSpotOrderTrigger = "last"
+	KrakenSpotOrderTriggerIndex KrakenSpotOrderTrigger = "index"
+)
+
+func (t KrakenSpotOrderTrigger) String() string {
+	return string(t)
+}
+
+func (t KrakenSpotOrderTrigger) Valid() bool {
+	return t == KrakenSpotOrderTriggerLast || t == KrakenSpotOrderTriggerIndex
+}
+
+// KrakenSpotSelfTradePrevention is which order is cancelled when an order
+// would match against another order of the same account.
+type KrakenSpotSelfTradePrevention string
+
+const (
+	KrakenSpotSelfTradeCancelNewest KrakenSpotSelfTradePrevention = "cancel-newest"
+	KrakenSpotSelfTradeCancelOldest KrakenSpotSelfTradePrevention = "cancel-oldest"
+	KrakenSpotSelfTradeCancelBoth   KrakenSpotSelfTradePrevention = "cancel-both"
+)
+
+func (s KrakenSpotSelfTradePrevention) String() string {
+	return string(s)
+}
+
+func (s KrakenSpotSelfTradePrevention) Valid() bool {
+	switch s {
+	case KrakenSpotSelfTradeCancelNewest, KrakenSpotSelfTradeCancelOldest, KrakenSpotSelfTradeCancelBoth:
+		return true
+	}
+	return false
+}
+
+// KrakenSpotOrderFlags is the comma separated list of order flags Kraken
+// reports on an order.
+type KrakenSpotOrderFlags []KrakenSpotOrderFlag
+
+// Has reports whether flag is set.
+func (f KrakenSpotOrderFlags) Has(flag KrakenSpotOrderFlag) bool {
+	return slices.Contains(f, flag)
+}
+
+func (f KrakenSpotOrderFlags) String() string {
+	return joinOrderFlags(f)
+}
+
+func (f *KrakenSpotOrderFlags) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*f = nil
+	for _, flag := range strings.Split(s, ",") {
+		if len(flag) > 0 {
+			*f = append(*f, KrakenSpotOrderFlag(flag))
+		}
+	}
+	return nil
+}
+
+func (f KrakenSpotOrderFlags) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.String())
+}
+
+// KrakenSpotOrderStatus is the status of a spot order.
+type KrakenSpotOrderStatus string
+
+const (
+	KrakenSpotOrderPending  KrakenSpotOrderStatus = "pending"
+	KrakenSpotOrderOpen     KrakenSpotOrderStatus = "open"
+	KrakenSpotOrderClosed   KrakenSpotOrderStatus = "closed"
+	KrakenSpotOrderCanceled KrakenSpotOrderStatus = "canceled"
+	KrakenSpotOrderExpired  KrakenSpotOrderStatus = "expired"
+)
+
+func (s KrakenSpotOrderStatus) String() string {
+	return string(s)
+}
+
+func (s KrakenSpotOrderStatus) Valid() bool {
+	switch s {
+	case KrakenSpotOrderPending, KrakenSpotOrderOpen, KrakenSpotOrderClosed, KrakenSpotOrderCanceled, KrakenSpotOrderExpired:
+		return true
+	}
+	return false
+}
+
+// KrakenSpotCloseTime selects which time of a closed order the start and end
+// filters of GetClosedOrders apply to.
+type KrakenSpotCloseTime string
+
+const (
+	KrakenSpotCloseTimeOpen  KrakenSpotCloseTime = "open"
+	KrakenSpotCloseTimeClose KrakenSpotCloseTime = "close"
+	KrakenSpotCloseTimeBoth  KrakenSpotCloseTime = "both"
+)
+
+func (t KrakenSpotCloseTime) String() string {
+	return string(t)
+}
+
+func (t KrakenSpotCloseTime) Valid() bool {
+	return t == KrakenSpotCloseTimeOpen || t == KrakenSpotCloseTimeClose || t == KrakenSpotCloseTimeBoth
+}
+
+// KrakenSpotTradeHistoryType selects the trades returned by GetTradesHistory
+// by their effect on margin positions.
+type KrakenSpotTradeHistoryType string
+
+const (
+	KrakenSpotTradeHistoryAll             KrakenSpotTradeHistoryType = "all"
+	KrakenSpotTradeHistoryAnyPosition     KrakenSpotTradeHistoryType = "any position"
+	KrakenSpotTradeHistoryClosedPosition  KrakenSpotTradeHistoryType = "closed position"
+	KrakenSpotTradeHistoryClosingPosition KrakenSpotTradeHistoryType = "closing position"
+	KrakenSpotTradeHistoryNoPosition      KrakenSpotTradeHistoryType = "no position"
+)
+
+func (t KrakenSpotTradeHistoryType) String() string {
+	return string(t)
+}
+
+func (t KrakenSpotTradeHistoryType) Valid() bool {
+	switch t {
+	case KrakenSpotTradeHistoryAll, KrakenSpotTradeHistoryAnyPosition, KrakenSpotTradeHistoryClosedPosition,
+		KrakenSpotTradeHistoryClosingPosition, KrakenSpotTradeHistoryNoPosition:
+		return true
+	}
+	return false
+}
+
+// KrakenSpotLedgerType is the kind of a ledger entry.
+type KrakenSpotLedgerType string
+
+const (
+	KrakenSpotLedgerAll        KrakenSpotLedgerType = "all"
+	KrakenSpotLedgerTrade      KrakenSpotLedgerType = "trade"
+	KrakenSpotLedgerDeposit    KrakenSpotLedgerType = "deposit"
+	KrakenSpotLedgerWithdrawal KrakenSpotLedgerType = "withdrawal"
+	KrakenSpotLedgerTransfer   KrakenSpotLedgerType = "transfer"
+	KrakenSpotLedgerMargin     KrakenSpotLedgerType = "margin"
+	KrakenSpotLedgerRollover   KrakenSpotLedgerType = "rollover"
+	KrakenSpotLedgerSpend      KrakenSpotLedgerType = "spend"
+	KrakenSpotLedgerReceive    KrakenSpotLedgerType = "receive"
+	KrakenSpotLedgerSettled    KrakenSpotLedgerType = "settled"
+	KrakenSpotLedgerAdjustment KrakenSpotLedgerType = "adjustment"
+	KrakenSpotLedgerStaking    KrakenSpotLedgerType = "staking"
+	KrakenSpotLedgerSale       KrakenSpotLedgerType = "sale"
+	KrakenSpotLedgerDividend   KrakenSpotLedgerType = "dividend"
+	KrakenSpotLedgerCredit     KrakenSpotLedgerType = "credit"
+)
+
+func (t KrakenSpotLedgerType) String() string {
+	return string(t)
+}
+
+func (t KrakenSpotLedgerType) Valid() bool {
+	switch t {
+	case KrakenSpotLedgerAll, KrakenSpotLedgerTrade, KrakenSpotLedgerDeposit, KrakenSpotLedgerWithdrawal,
+		KrakenSpotLedgerTransfer, KrakenSpotLedgerMargin, KrakenSpotLedgerRollover, KrakenSpotLedgerSpend,
+		KrakenSpotLedgerReceive, KrakenSpotLedgerSettled, KrakenSpotLedgerAdjustment, KrakenSpotLedgerStaking,
+		KrakenSpotLedgerSale, KrakenSpotLedgerDividend, KrakenSpotLedgerCredit:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package kraken
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestKrakenSideUnmarshalJSON(t *testing.T) {
+	for _, tt := range []struct {
+		in    string
+		want  KrakenSide
+		valid bool
+	}{
+		{`"b"`, KrakenSideBuy, true},
+		{`"sell"`, KrakenSideSell, true},
+		{`"short"`, KrakenSide("short"), false},
+	} {
+		var got KrakenSide
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("Unmarshal(%s) error = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want || got.Valid() != tt.valid {
+			t.Errorf("Unmarshal(%s) = %q, Valid() = %t, want %q, %t", tt.in, got, got.Valid(), tt.want, tt.valid)
+		}
+	}
+
+	if _, err := ParseKrakenSide("short"); err == nil {
+		t.Error(`ParseKrakenSide("short") succeeded`)
+	}
+	if _, err := (KrakenSpotOrderRequest{Pair: "XBTUSD", Side: "short", OrderType: KrakenOrderTypeMarket}).params(); err == nil {
+		t.Error("params() accepted the side short")
+	}
+}
+
+func TestKrakenOrderTypeUnmarshalJSON(t *testing.T) {
+	for _, tt := range []struct {
+		in    string
+		want  KrakenOrderType
+		valid bool
+	}{
+		{`"m"`, KrakenOrderTypeMarket, true},
+		{`"l"`, KrakenOrderTypeLimit, true},
+		{`"stop-loss-limit"`, KrakenOrderTypeStopLossLimit, true},
+		{`"new-type"`, KrakenOrderType("new-type"), false},
+	} {
+		var got KrakenOrderType
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Errorf("Unmarshal(%s) error = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want || got.Valid() != tt.valid {
+			t.Errorf("Unmarshal(%s) = %q, Valid() = %t, want %q, %t", tt.in, got, got.Valid(), tt.want, tt.valid)
+		}
+	}
+
+	if _, err := ParseKrakenOrderType("new-type"); err == nil {
+		t.Error(`ParseKrakenOrderType("new-type") succeeded`)
+	}
+}
+
+func TestKrakenSpotFillUnknownEnums(t *testing.T) {
+	var fills map[string]KrakenSpotFill
+	data := `{"TCWJEG-FL4SZ-3FKGH6":{"ordertxid":"OQCLML-BW3P3-BUCMWZ","pair":"XXBTZUSD","time":1688667796.8802,"type":"short","ordertype":"new-type","price":"30010.00000","vol":"0.02000000"}}`
+	if err := json.Unmarshal([]byte(data), &fills); err != nil {
+		t.Fatalf("Unmarshal error = %v", err)
+	}
+	fill := fills["TCWJEG-FL4SZ-3FKGH6"]
+	if fill.OrderType != "new-type" || fill.OrderType.Valid() {
+		t.Errorf("OrderType = %q, Valid() = %t", fill.OrderType, fill.OrderType.Valid())
+	}
+	if fill.Side != "short" || fill.Side.Valid() {
+		t.Errorf("Side = %q, Valid() = %t", fill.Side, fill.Side.Valid())
+	}
+	if fill.Price.String() != "30010.00000" {
+		t.Errorf("decoded %+v", fill)
+	}
+}
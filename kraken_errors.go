@@ -0,0 +1,199 @@
+package kraken
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Classes of errors reported by Kraken. KrakenError and HTTPError match them
+// with errors.Is, e.g. errors.Is(err, ErrRateLimitExceeded).
+var (
+	ErrRateLimitExceeded  = errors.New("kraken: rate limit exceeded")
+	ErrInvalidArguments   = errors.New("kraken: invalid arguments")
+	ErrServiceUnavailable = errors.New("kraken: service unavailable")
+	ErrServiceBusy        = errors.New("kraken: service busy")
+	// ErrMarketRestricted is returned for orders a market in cancel_only or
+	// post_only mode does not accept. The mode is set by Kraken and lasts
+	// longer than a retry would wait, so it is not retried.
+	ErrMarketRestricted  = errors.New("kraken: market restricted")
+	ErrPermissionDenied  = errors.New("kraken: permission denied")
+	ErrAuthentication    = errors.New("kraken: authentication failed")
+	ErrInvalidNonce      = errors.New("kraken: invalid nonce")
+	ErrUnknownAssetPair  = errors.New("kraken: unknown asset pair")
+	ErrUnknownAsset      = errors.New("kraken: unknown asset")
+	ErrInsufficientFunds = errors.New("kraken: insufficient funds")
+	ErrInternal          = errors.New("kraken: internal error")
+)
+
+// ErrPairNotFound is returned when a response holds no result for the
+// requested pair.
+var ErrPairNotFound = errors.New("kraken: pair not found in response")
+
+// krakenErrorCodes maps the error codes returned by the spot ("Category:Message")
+// and futures (camel case identifiers) APIs to their error class.
+var krakenErrorCodes = map[string]error{
+	"EAPI:Rate limit exceeded":            ErrRateLimitExceeded,
+	"EOrder:Rate limit exceeded":          ErrRateLimitExceeded,
+	"EGeneral:Too many requests":          ErrRateLimitExceeded,
+	"EGeneral:Invalid arguments":          ErrInvalidArguments,
+	"EService:Unavailable":                ErrServiceUnavailable,
+	"EService:Market in cancel_only mode": ErrMarketRestricted,
+	"EService:Market in post_only mode":   ErrMarketRestricted,
+	"EService:Busy":                       ErrServiceBusy,
+	"EGeneral:Permission denied":          ErrPermissionDenied,
+	"EAPI:Invalid key":                    ErrAuthentication,
+	"EAPI:Invalid signature":              ErrAuthentication,
+	"EAPI:Invalid nonce":                  ErrInvalidNonce,
+	"EQuery:Unknown asset pair":           ErrUnknownAssetPair,
+	"EQuery:Unknown asset":                ErrUnknownAsset,
+	"EOrder:Insufficient funds":           ErrInsufficientFunds,
+	"EGeneral:Internal error":             ErrInternal,
+	"apiLimitExceeded":                    ErrRateLimitExceeded,
+	"invalidArgument":                     ErrInvalidArguments,
+	"requiredArgumentMissing":             ErrInvalidArguments,
+	"invalidUnit":                         ErrInvalidArguments,
+	"marketUnavailable":                   ErrServiceUnavailable,
+	"Unavailable":                         ErrServiceUnavailable,
+	"authenticationError":                 ErrAuthentication,
+	"nonceBelowThreshold":                 ErrInvalidNonce,
+	"nonceDuplicate":                      ErrInvalidNonce,
+	"insufficientAvailableFunds":          ErrInsufficientFunds,
+	"Server Error":                        ErrInternal,
+}
+
+// KrakenError is an error reported by Kraken in the body of a response.
+type KrakenError struct {
+	// Message is the first error message, kept for callers that only expect one.
+	Message string
+	// Messages holds every error message in the response.
+	Messages []string
+}
+
+func newKrakenSpotError(errs []string) *KrakenError {
+	return &KrakenError{Message: errs[0], Messages: errs}
+}
+
+func newKrakenFuturesError(err string, errs []string) *KrakenError {
+	messages := make([]string, 0, len(errs)+1)
+	if len(err) > 0 {
+		messages = append(messages, err)
+	}
+	messages = append(messages, errs...)
+	if len(messages) == 0 {
+		messages = append(messages, "unknown error")
+	}
+	return &KrakenError{Message: messages[0], Messages: messages}
+}
+
+func (e *KrakenError) Error() string {
+	if len(e.Messages) > 1 {
+		return strings.Join(e.Messages, "; ")
+	}
+	return e.Message
+}
+
+// Is reports whether any of the error messages belongs to the error class target.
+func (e *KrakenError) Is(target error) bool {
+	messages := e.Messages
+	if len(messages) == 0 {
+		messages = []string{e.Message}
+	}
+	for _, message := range messages {
+		if classifyKrakenError(message) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyKrakenError returns the error class of a Kraken error message or nil
+// if the message is not known. Spot messages may carry extra detail after the
+// code, e.g. "EGeneral:Invalid arguments:volume minimum not met".
+func classifyKrakenError(message string) error {
+	if err, ok := krakenErrorCodes[message]; ok {
+		return err
+	}
+	for code, err := range krakenErrorCodes {
+		if strings.HasPrefix(message, code+":") {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeKrakenSpotError returns the error carried in a spot response body, if any.
+func decodeKrakenSpotError(body []byte) error {
+	var d struct {
+		Error []string `json:"error"`
+	}
+	if json.Unmarshal(body, &d) != nil || len(d.Error) == 0 {
+		return nil
+	}
+	return newKrakenSpotError(d.Error)
+}
+
+// decodeKrakenFuturesError returns the error carried in a futures response body, if any.
+func decodeKrakenFuturesError(body []byte) error {
+	var d struct {
+		Result string   `json:"result"`
+		Error  string   `json:"error"`
+		Errors []string `json:"errors"`
+	}
+	if json.Unmarshal(body, &d) != nil {
+		return nil
+	}
+	if d.Result != "error" && len(d.Error) == 0 && len(d.Errors) == 0 {
+		return nil
+	}
+	return newKrakenFuturesError(d.Error, d.Errors)
+}
+
+// maxHTTPErrorBody bounds the amount of a non-2xx response body kept in an HTTPError.
+const maxHTTPErrorBody = 512
+
+// HTTPError is returned when Kraken responds with a non-2xx status code.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	// Body is the beginning of the response body.
+	Body []byte
+	// Err is the Kraken error decoded from the body, if it carried one.
+	Err error
+}
+
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("kraken: http status %s", e.Status)
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	if len(e.Body) > 0 {
+		return msg + ": " + string(e.Body)
+	}
+	return msg
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Is classifies the status code, so a 429 matches ErrRateLimitExceeded even when
+// the body carries no Kraken error.
+func (e *HTTPError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimitExceeded
+	case http.StatusServiceUnavailable:
+		return target == ErrServiceUnavailable
+	case http.StatusUnauthorized:
+		return target == ErrAuthentication
+	case http.StatusForbidden:
+		return target == ErrPermissionDenied
+	case http.StatusInternalServerError:
+		return target == ErrInternal
+	}
+	return false
+}
@@ -1,41 +1,36 @@
 package kraken
 
 import (
+	"context"
 	"encoding/json"
-	"io"
-	"net/http"
 	"net/url"
 )
 
 type KrakenFuturesHttpClient struct {
-	baseURL string
+	baseURL   string
+	transport *httpTransport
 }
 
-func NewKrakenFuturesHttpClient() *KrakenFuturesHttpClient {
-	return &KrakenFuturesHttpClient{baseURL: "https://futures.kraken.com"}
-}
-
-func (c *KrakenFuturesHttpClient) get(url string) ([]byte, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+func NewKrakenFuturesHttpClient(opts ...ClientOption) *KrakenFuturesHttpClient {
+	o := newClientOptions(KrakenFuturesBaseURL, opts)
+	return &KrakenFuturesHttpClient{
+		baseURL:   o.baseURL,
+		transport: newHttpTransport(o, decodeKrakenFuturesError),
 	}
-	defer resp.Body.Close()
+}
 
-	// TODO: check response code here.
-	// I think I always want a 2XX code
+func (c *KrakenFuturesHttpClient) get(ctx context.Context, url string) ([]byte, error) {
+	return c.transport.get(ctx, url)
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	return body, nil
+func (c *KrakenFuturesHttpClient) GetInstruments() ([]KrakenFuturesInstrument, error) {
+	return c.GetInstrumentsWithContext(context.Background())
 }
 
-func (c *KrakenFuturesHttpClient) GetInstruments() ([]map[string]any, error) {
+func (c *KrakenFuturesHttpClient) GetInstrumentsWithContext(ctx context.Context) ([]KrakenFuturesInstrument, error) {
 	endpoint := "/derivatives/api/v3/instruments"
 	url := c.baseURL + endpoint
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -47,17 +42,20 @@ func (c *KrakenFuturesHttpClient) GetInstruments() ([]map[string]any, error) {
 	}
 
 	if d.Result == "error" {
-		// FIXME: include the array of errors
-		return nil, &KrakenError{d.Error}
+		return nil, newKrakenFuturesError(d.Error, d.Errors)
 	}
 
 	return d.Instruments, nil
 }
 
 func (c *KrakenFuturesHttpClient) GetInstrumentStatus(symbol string) (KrakenFuturesInstrumentStatus, error) {
+	return c.GetInstrumentStatusWithContext(context.Background(), symbol)
+}
+
+func (c *KrakenFuturesHttpClient) GetInstrumentStatusWithContext(ctx context.Context, symbol string) (KrakenFuturesInstrumentStatus, error) {
 	endpoint := "/derivatives/api/v3/instruments/" + symbol + "/status"
 	url := c.baseURL + endpoint
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return KrakenFuturesInstrumentStatus{}, err
 	}
@@ -69,8 +67,7 @@ func (c *KrakenFuturesHttpClient) GetInstrumentStatus(symbol string) (KrakenFutu
 	}
 
 	if d.Result == "error" {
-		// FIXME: include the array of errors
-		return KrakenFuturesInstrumentStatus{}, &KrakenError{d.Error}
+		return KrakenFuturesInstrumentStatus{}, newKrakenFuturesError(d.Error, d.Errors)
 	}
 
 	status := KrakenFuturesInstrumentStatus{
@@ -85,9 +82,13 @@ func (c *KrakenFuturesHttpClient) GetInstrumentStatus(symbol string) (KrakenFutu
 }
 
 func (c *KrakenFuturesHttpClient) GetTicker(symbol string) (KrakenFuturesTickerInfo, error) {
+	return c.GetTickerWithContext(context.Background(), symbol)
+}
+
+func (c *KrakenFuturesHttpClient) GetTickerWithContext(ctx context.Context, symbol string) (KrakenFuturesTickerInfo, error) {
 	endpoint := "/derivatives/api/v3/tickers"
 	url := c.baseURL + endpoint + "/" + symbol
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return KrakenFuturesTickerInfo{}, err
 	}
@@ -99,13 +100,17 @@ func (c *KrakenFuturesHttpClient) GetTicker(symbol string) (KrakenFuturesTickerI
 	}
 
 	if d.Result == "error" {
-		return KrakenFuturesTickerInfo{}, &KrakenError{d.Error}
+		return KrakenFuturesTickerInfo{}, newKrakenFuturesError(d.Error, d.Errors)
 	}
 
 	return d.Ticker, nil
 }
 
 func (c *KrakenFuturesHttpClient) GetTradeHistory(symbol string, lastTime string) ([]KrakenFuturesTradeInfo, error) {
+	return c.GetTradeHistoryWithContext(context.Background(), symbol, lastTime)
+}
+
+func (c *KrakenFuturesHttpClient) GetTradeHistoryWithContext(ctx context.Context, symbol string, lastTime string) ([]KrakenFuturesTradeInfo, error) {
 	// query params
 	params := url.Values{}
 	params.Add("symbol", symbol)
@@ -118,7 +123,7 @@ func (c *KrakenFuturesHttpClient) GetTradeHistory(symbol string, lastTime string
 
 	endpoint := "/derivatives/api/v3/history"
 	url := c.baseURL + endpoint + "?" + queryString
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -130,13 +135,17 @@ func (c *KrakenFuturesHttpClient) GetTradeHistory(symbol string, lastTime string
 	}
 
 	if d.Result == "error" {
-		return nil, &KrakenError{d.Error}
+		return nil, newKrakenFuturesError(d.Error, d.Errors)
 	}
 
 	return d.History, nil
 }
 
 func (c *KrakenFuturesHttpClient) GetOrderBook(symbol string) (KrakenFuturesOrderBook, error) {
+	return c.GetOrderBookWithContext(context.Background(), symbol)
+}
+
+func (c *KrakenFuturesHttpClient) GetOrderBookWithContext(ctx context.Context, symbol string) (KrakenFuturesOrderBook, error) {
 
 	// query params
 	params := url.Values{}
@@ -145,7 +154,7 @@ func (c *KrakenFuturesHttpClient) GetOrderBook(symbol string) (KrakenFuturesOrde
 
 	endpoint := "/derivatives/api/v3/orderbook"
 	url := c.baseURL + endpoint + "?" + queryString
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return KrakenFuturesOrderBook{}, err
 	}
@@ -157,7 +166,7 @@ func (c *KrakenFuturesHttpClient) GetOrderBook(symbol string) (KrakenFuturesOrde
 	}
 
 	if d.Result == "error" {
-		return KrakenFuturesOrderBook{}, &KrakenError{d.Error}
+		return KrakenFuturesOrderBook{}, newKrakenFuturesError(d.Error, d.Errors)
 	}
 
 	return d.OrderBook, nil
@@ -165,6 +174,10 @@ func (c *KrakenFuturesHttpClient) GetOrderBook(symbol string) (KrakenFuturesOrde
 
 // FIXME: implement
 func (c *KrakenFuturesHttpClient) GetHistoricalFundingRates(symbol string) ([]KrakenFuturesFundingRate, error) {
+	return c.GetHistoricalFundingRatesWithContext(context.Background(), symbol)
+}
+
+func (c *KrakenFuturesHttpClient) GetHistoricalFundingRatesWithContext(ctx context.Context, symbol string) ([]KrakenFuturesFundingRate, error) {
 	// query params
 	params := url.Values{}
 	params.Add("symbol", symbol)
@@ -172,7 +185,7 @@ func (c *KrakenFuturesHttpClient) GetHistoricalFundingRates(symbol string) ([]Kr
 
 	endpoint := "/derivatives/api/v3/historical-funding-rates"
 	url := c.baseURL + endpoint + "?" + queryString
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -184,7 +197,7 @@ func (c *KrakenFuturesHttpClient) GetHistoricalFundingRates(symbol string) ([]Kr
 	}
 
 	if d.Result == "error" {
-		return nil, &KrakenError{d.Error}
+		return nil, newKrakenFuturesError(d.Error, d.Errors)
 	}
 
 	return d.Rates, nil
@@ -0,0 +1,133 @@
+package kraken
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// httpTransport holds the request machinery shared by the spot and futures
+// clients.
+type httpTransport struct {
+	client    *http.Client
+	userAgent string
+	retry     RetryPolicy
+	limiter   RateLimiter
+	// basePath is the path of the base URL, stripped from request paths to
+	// name endpoints for the rate limiter.
+	basePath string
+	// decodeError extracts the Kraken error carried by a response body.
+	decodeError func([]byte) error
+}
+
+func newHttpTransport(o clientOptions, decodeError func([]byte) error) *httpTransport {
+	return &httpTransport{
+		client:      o.httpClient,
+		userAgent:   o.userAgent,
+		retry:       o.retry,
+		limiter:     o.limiter,
+		basePath:    basePath(o.baseURL),
+		decodeError: decodeError,
+	}
+}
+
+func basePath(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(u.Path, "/")
+}
+
+func (t *httpTransport) get(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimPrefix(u.Path, t.basePath)
+
+	newRequest := func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	}
+	return t.do(ctx, endpoint, newRequest, true)
+}
+
+// do sends the request built by newRequest, retrying transient failures as
+// configured by the retry policy. Requests that are not idempotent are only
+// retried if the policy allows it. newRequest is called for every attempt,
+// after the rate limiter has let the attempt for endpoint through; an error
+// building the request is returned without retrying.
+func (t *httpTransport) do(ctx context.Context, endpoint string, newRequest func() (*http.Request, error), idempotent bool) ([]byte, error) {
+	maxAttempts := t.retry.MaxAttempts
+	if !idempotent && !t.retry.RetryNonIdempotent {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		if t.limiter != nil {
+			if err := t.limiter.Wait(ctx, endpoint); err != nil {
+				return nil, err
+			}
+		}
+
+		// failing to build the request, e.g. to get a nonce or a one time
+		// password, is not a transient failure of the exchange
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := t.doOnce(req)
+		if err == nil {
+			// Kraken reports some transient failures in the body of a 2XX response
+			krakenErr := t.decodeError(body)
+			if krakenErr == nil || !isRetryable(ctx, krakenErr) {
+				return body, nil
+			}
+			err = krakenErr
+		}
+
+		if attempt >= maxAttempts || !isRetryable(ctx, err) {
+			return nil, err
+		}
+
+		delay := max(t.retry.backoff(attempt), retryAfter(err))
+		if sleep(ctx, delay) != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *httpTransport) doOnce(req *http.Request) ([]byte, error) {
+	if len(t.userAgent) > 0 {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, t.newHTTPError(resp, body)
+	}
+	return body, nil
+}
+
+func (t *httpTransport) newHTTPError(resp *http.Response, body []byte) *HTTPError {
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       body[:min(len(body), maxHTTPErrorBody)],
+		Err:        t.decodeError(body),
+	}
+}
@@ -1,12 +1,11 @@
 package kraken
 
-type KrakenError struct {
-	Message string
-}
-
-func (e *KrakenError) Error() string {
-	return e.Message
-}
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type KrakenSpotResponse struct {
 	Error  []string       `json:"error"`
@@ -18,14 +17,18 @@ type KrakenSpotServerTime struct {
 	RFC1123  string `json:"rfc1123"`
 }
 
+func (t KrakenSpotServerTime) Time() time.Time {
+	return time.Unix(t.UnixTime, 0).UTC()
+}
+
 type KrakenSpotServerTimeResponse struct {
 	Error  []string             `json:"error"`
 	Result KrakenSpotServerTime `json:"result"`
 }
 
 type KrakenSpotSystemStatus struct {
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp"`
+	Status    KrakenSpotSystemState `json:"status"`
+	Timestamp KrakenTime            `json:"timestamp"`
 }
 
 type KrakenSpotSystemStatusResponse struct {
@@ -34,13 +37,13 @@ type KrakenSpotSystemStatusResponse struct {
 }
 
 type KrakenSpotAssetInfo struct {
-	AssetClass      string  `json:"aclass"`
-	AlternateName   string  `json:"altname"`
-	Decimals        int     `json:"decimals"`
-	DisplayDecimals int     `json:"display_decimals"`
-	CollateralValue float64 `json:"collateral_value,omitempty"`
-	Status          string  `json:"status"`
-	MarginRate      string  `json:"margin_rate,omitempty"`
+	AssetClass      KrakenAssetClass      `json:"aclass"`
+	AlternateName   string                `json:"altname"`
+	Decimals        int                   `json:"decimals"`
+	DisplayDecimals int                   `json:"display_decimals"`
+	CollateralValue Decimal               `json:"collateral_value,omitempty"`
+	Status          KrakenSpotAssetStatus `json:"status"`
+	MarginRate      Decimal               `json:"margin_rate,omitempty"`
 }
 
 type KrakenSpotAssetInfoResponse struct {
@@ -49,45 +52,147 @@ type KrakenSpotAssetInfoResponse struct {
 }
 
 type KrakenSpotAssetPairInfo struct {
-	AlternateName      string  `json:"altname"`
-	AssetClassBase     string  `json:"aclass_base"`
-	Base               string  `json:"base"`
-	AssetClasQuote     string  `json:"aclass_quote"`
-	Quote              string  `json:"quote"`
-	Lot                string  `json:"lot,omitempty"`
-	CostDecimals       int     `json:"cost_decimals"`
-	PairDecimals       int     `json:"pair_decimals"`
-	LotDecimals        int     `json:"lot_decimals"`
-	LotMultiplier      int     `json:"lot_multiplier"`
-	LeverageBuy        []int   `json:"leverage_buy"`
-	LeverageSell       []int   `json:"leverage_sell"`
-	Fees               [][]any `json:"fees"`
-	FeesMaker          [][]any `json:"fees_maker"`
-	FeeVolumneCurrency string  `json:"fees_volume_currency"`
-	MarginCall         int     `json:"margin_call"`
-	MarginStop         int     `json:"margin_stop"`
-	CostMin            string  `json:"costmin"`
-	TickSize           string  `json:"tick_size"`
-	Status             string  `json:"status"`
-	LongPositionLimit  int     `json:"long_position_limit"`
-	ShortPositionLimit int     `json:"short_position_limit"`
+	AlternateName      string               `json:"altname"`
+	WebsocketName      string               `json:"wsname"`
+	AssetClassBase     KrakenAssetClass     `json:"aclass_base"`
+	Base               string               `json:"base"`
+	AssetClasQuote     KrakenAssetClass     `json:"aclass_quote"`
+	Quote              string               `json:"quote"`
+	Lot                string               `json:"lot,omitempty"`
+	CostDecimals       int                  `json:"cost_decimals"`
+	PairDecimals       int                  `json:"pair_decimals"`
+	LotDecimals        int                  `json:"lot_decimals"`
+	LotMultiplier      int                  `json:"lot_multiplier"`
+	LeverageBuy        []int                `json:"leverage_buy"`
+	LeverageSell       []int                `json:"leverage_sell"`
+	Fees               []KrakenSpotFeeTier  `json:"fees"`
+	FeesMaker          []KrakenSpotFeeTier  `json:"fees_maker"`
+	FeeVolumneCurrency string               `json:"fees_volume_currency"`
+	MarginCall         int                  `json:"margin_call"`
+	MarginStop         int                  `json:"margin_stop"`
+	OrderMin           Decimal              `json:"ordermin"`
+	CostMin            Decimal              `json:"costmin"`
+	TickSize           Decimal              `json:"tick_size"`
+	Status             KrakenSpotPairStatus `json:"status"`
+	LongPositionLimit  int                  `json:"long_position_limit"`
+	ShortPositionLimit int                  `json:"short_position_limit"`
+}
+
+// RoundPrice rounds price to the number of decimals the pair is quoted with.
+func (p KrakenSpotAssetPairInfo) RoundPrice(price Decimal) Decimal {
+	return price.Round(int32(p.PairDecimals))
+}
+
+// RoundVolume truncates volume to the number of decimals the pair's volume is
+// expressed with. It never rounds up, so the result does not exceed volume.
+func (p KrakenSpotAssetPairInfo) RoundVolume(volume Decimal) Decimal {
+	return volume.Truncate(int32(p.LotDecimals))
 }
 
+// [<volume>, <percent fee>]
+type KrakenSpotFeeTier struct {
+	Volume     Decimal
+	PercentFee Decimal
+}
+
+func (t *KrakenSpotFeeTier) UnmarshalJSON(data []byte) error {
+	d := newPositionalDecoder("KrakenSpotFeeTier", data, 2)
+	t.Volume = d.decimal(0, "volume")
+	t.PercentFee = d.decimal(1, "percent fee")
+	return d.err
+}
+
+// KrakenSpotAssetPairsInfo selects the fields returned by the AssetPairs endpoint.
+type KrakenSpotAssetPairsInfo string
+
+const (
+	KrakenSpotAssetPairsInfoAll      KrakenSpotAssetPairsInfo = "info"
+	KrakenSpotAssetPairsInfoLeverage KrakenSpotAssetPairsInfo = "leverage"
+	KrakenSpotAssetPairsInfoFees     KrakenSpotAssetPairsInfo = "fees"
+	KrakenSpotAssetPairsInfoMargin   KrakenSpotAssetPairsInfo = "margin"
+)
+
 type KrakenSpotAssetPairResponse struct {
 	Error  []string                           `json:"error"`
 	Result map[string]KrakenSpotAssetPairInfo `json:"result"`
 }
 
 type KrakenSpotAssetTickerInfo struct {
-	Ask        []string `json:"a"`
-	Bid        []string `json:"b"`
-	LastTrade  []string `json:"c"`
-	Volume     []string `json:"v"`
-	VWAP       []string `json:"p"`
-	TradeCount []int    `json:"t"`
-	Low        []string `json:"l"`
-	High       []string `json:"h"`
-	Open       string   `json:"o"`
+	Ask        []Decimal `json:"a"`
+	Bid        []Decimal `json:"b"`
+	LastTrade  []Decimal `json:"c"`
+	Volume     []Decimal `json:"v"`
+	VWAP       []Decimal `json:"p"`
+	TradeCount []int     `json:"t"`
+	Low        []Decimal `json:"l"`
+	High       []Decimal `json:"h"`
+	Open       Decimal   `json:"o"`
+}
+
+// KrakenSpotTicker is the parsed view of KrakenSpotAssetTickerInfo, with each
+// positional value in a named field. "Today" values cover the period since
+// 00:00:00 UTC, "24h" values the last 24 hours.
+type KrakenSpotTicker struct {
+	AskPrice          Decimal
+	AskWholeLotVolume Decimal
+	AskLotVolume      Decimal
+	BidPrice          Decimal
+	BidWholeLotVolume Decimal
+	BidLotVolume      Decimal
+	LastTradePrice    Decimal
+	LastTradeVolume   Decimal
+	VolumeToday       Decimal
+	Volume24h         Decimal
+	VWAPToday         Decimal
+	VWAP24h           Decimal
+	TradeCountToday   int
+	TradeCount24h     int
+	LowToday          Decimal
+	Low24h            Decimal
+	HighToday         Decimal
+	High24h           Decimal
+	Open              Decimal
+}
+
+// Parse returns the ticker with named fields. It fails if any of the
+// positional arrays is shorter than Kraken documents.
+func (t KrakenSpotAssetTickerInfo) Parse() (KrakenSpotTicker, error) {
+	fields := []struct {
+		name     string
+		length   int
+		expected int
+	}{
+		{"a", len(t.Ask), 3}, {"b", len(t.Bid), 3}, {"c", len(t.LastTrade), 2},
+		{"v", len(t.Volume), 2}, {"p", len(t.VWAP), 2}, {"t", len(t.TradeCount), 2},
+		{"l", len(t.Low), 2}, {"h", len(t.High), 2},
+	}
+	for _, field := range fields {
+		if field.length < field.expected {
+			return KrakenSpotTicker{}, fmt.Errorf("kraken: ticker field %s: expected %d values, got %d", field.name, field.expected, field.length)
+		}
+	}
+
+	return KrakenSpotTicker{
+		AskPrice:          t.Ask[0],
+		AskWholeLotVolume: t.Ask[1],
+		AskLotVolume:      t.Ask[2],
+		BidPrice:          t.Bid[0],
+		BidWholeLotVolume: t.Bid[1],
+		BidLotVolume:      t.Bid[2],
+		LastTradePrice:    t.LastTrade[0],
+		LastTradeVolume:   t.LastTrade[1],
+		VolumeToday:       t.Volume[0],
+		Volume24h:         t.Volume[1],
+		VWAPToday:         t.VWAP[0],
+		VWAP24h:           t.VWAP[1],
+		TradeCountToday:   t.TradeCount[0],
+		TradeCount24h:     t.TradeCount[1],
+		LowToday:          t.Low[0],
+		Low24h:            t.Low[1],
+		HighToday:         t.High[0],
+		High24h:           t.High[1],
+		Open:              t.Open,
+	}, nil
 }
 
 type KrakenTickerResponse struct {
@@ -95,12 +200,20 @@ type KrakenTickerResponse struct {
 	Result map[string]KrakenSpotAssetTickerInfo `json:"result"`
 }
 
-// type KrakenSpotPriceLevel struct {
-// 	data []any
-// }
-
 // [<price>, <volume>, <timestamp>]
-type KrakenSpotPriceLevel []any
+type KrakenSpotPriceLevel struct {
+	Price     Decimal
+	Volume    Decimal
+	Timestamp KrakenTime
+}
+
+func (l *KrakenSpotPriceLevel) UnmarshalJSON(data []byte) error {
+	d := newPositionalDecoder("KrakenSpotPriceLevel", data, 3)
+	l.Price = d.decimal(0, "price")
+	l.Volume = d.decimal(1, "volume")
+	l.Timestamp = d.time(2, "timestamp")
+	return d.err
+}
 
 type KrakenSpotOrderBook struct {
 	Asks []KrakenSpotPriceLevel `json:"asks"`
@@ -112,32 +225,209 @@ type KrakenSpotDepthResponse struct {
 	Result map[string]KrakenSpotOrderBook `json:"result"`
 }
 
+// [<price>, <volume>, <time>, <buy/sell>, <market/limit>, <miscellaneous>, <trade_id>]
 type KrakenSpotTradeEntry struct {
-	Price              float64
-	Quantity           float64
-	TransactTime       float64
-	AggressorSide      string
-	AggressorOrderType string
+	Price              Decimal
+	Quantity           Decimal
+	TransactTime       KrakenTime
+	AggressorSide      KrakenSide
+	AggressorOrderType KrakenOrderType
 	Misc               string
-	TradeId            float64
+	TradeId            int64
+}
+
+func (e *KrakenSpotTradeEntry) UnmarshalJSON(data []byte) error {
+	d := newPositionalDecoder("KrakenSpotTradeEntry", data, 7)
+	e.Price = d.decimal(0, "price")
+	e.Quantity = d.decimal(1, "volume")
+	e.TransactTime = d.time(2, "time")
+	side, err := ParseKrakenSide(d.string(3, "buy/sell"))
+	d.check(3, "buy/sell", err)
+	orderType, err := ParseKrakenOrderType(d.string(4, "market/limit"))
+	d.check(4, "market/limit", err)
+	e.AggressorSide = side
+	e.AggressorOrderType = orderType
+	e.Misc = d.string(5, "miscellaneous")
+	e.TradeId = d.int64(6, "trade_id")
+	return d.err
 }
 
 type KrakenSpotTradeInfo struct {
 	Trades []KrakenSpotTradeEntry
-	Last   string
+	// Last.String() is the since value that polls for newer trades.
+	Last KrakenCursor
 }
 
 type KrakenSpotTradeResponse struct {
-	Error  []string       `json:"error"`
-	Result map[string]any `json:"result"`
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// KrakenSpotInterval is a candle interval in minutes.
+type KrakenSpotInterval int
+
+const (
+	KrakenSpotInterval1Minute   KrakenSpotInterval = 1
+	KrakenSpotInterval5Minutes  KrakenSpotInterval = 5
+	KrakenSpotInterval15Minutes KrakenSpotInterval = 15
+	KrakenSpotInterval30Minutes KrakenSpotInterval = 30
+	KrakenSpotInterval1Hour     KrakenSpotInterval = 60
+	KrakenSpotInterval4Hours    KrakenSpotInterval = 240
+	KrakenSpotInterval1Day      KrakenSpotInterval = 1440
+	KrakenSpotInterval1Week     KrakenSpotInterval = 10080
+	KrakenSpotInterval15Days    KrakenSpotInterval = 21600
+)
+
+// Valid reports whether the interval is supported by Kraken.
+func (i KrakenSpotInterval) Valid() bool {
+	switch i {
+	case KrakenSpotInterval1Minute, KrakenSpotInterval5Minutes, KrakenSpotInterval15Minutes,
+		KrakenSpotInterval30Minutes, KrakenSpotInterval1Hour, KrakenSpotInterval4Hours,
+		KrakenSpotInterval1Day, KrakenSpotInterval1Week, KrakenSpotInterval15Days:
+		return true
+	}
+	return false
+}
+
+// [<time>, <open>, <high>, <low>, <close>, <vwap>, <volume>, <count>]
+type KrakenSpotCandle struct {
+	Time   KrakenTime
+	Open   Decimal
+	High   Decimal
+	Low    Decimal
+	Close  Decimal
+	VWAP   Decimal
+	Volume Decimal
+	Count  int64
+}
+
+func (c *KrakenSpotCandle) UnmarshalJSON(data []byte) error {
+	d := newPositionalDecoder("KrakenSpotCandle", data, 8)
+	c.Time = d.time(0, "time")
+	c.Open = d.decimal(1, "open")
+	c.High = d.decimal(2, "high")
+	c.Low = d.decimal(3, "low")
+	c.Close = d.decimal(4, "close")
+	c.VWAP = d.decimal(5, "vwap")
+	c.Volume = d.decimal(6, "volume")
+	c.Count = d.int64(7, "count")
+	return d.err
+}
+
+type KrakenSpotOHLCInfo struct {
+	Candles []KrakenSpotCandle
+	// Last.String() is the since value that polls for newer candles.
+	Last KrakenCursor
+}
+
+type KrakenSpotOHLCResponse struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// [<time>, <bid>, <ask>]
+type KrakenSpotSpreadEntry struct {
+	Time KrakenTime
+	Bid  Decimal
+	Ask  Decimal
+}
+
+func (e *KrakenSpotSpreadEntry) UnmarshalJSON(data []byte) error {
+	d := newPositionalDecoder("KrakenSpotSpreadEntry", data, 3)
+	e.Time = d.time(0, "time")
+	e.Bid = d.decimal(1, "bid")
+	e.Ask = d.decimal(2, "ask")
+	return d.err
+}
+
+type KrakenSpotSpreadInfo struct {
+	Spreads []KrakenSpotSpreadEntry
+	// Last.String() is the since value that polls for newer spreads.
+	Last KrakenCursor
+}
+
+type KrakenSpotSpreadResponse struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// KrakenFuturesMarginLevel is a step of an instrument's margin schedule,
+// applying from Contracts contracts (or NumNonContractUnits for flexible
+// futures) upwards.
+type KrakenFuturesMarginLevel struct {
+	Contracts           int64   `json:"contracts"`
+	NumNonContractUnits Decimal `json:"numNonContractUnits"`
+	InitialMargin       Decimal `json:"initialMargin"`
+	MaintenanceMargin   Decimal `json:"maintenanceMargin"`
+}
+
+type KrakenFuturesInstrument struct {
+	Symbol                      string                     `json:"symbol"`
+	Type                        string                     `json:"type"`
+	Underlying                  string                     `json:"underlying,omitempty"`
+	UnderlyingFuture            string                     `json:"underlyingFuture,omitempty"`
+	Base                        string                     `json:"base,omitempty"`
+	Quote                       string                     `json:"quote,omitempty"`
+	Pair                        string                     `json:"pair,omitempty"`
+	Category                    string                     `json:"category,omitempty"`
+	Tags                        []string                   `json:"tags,omitempty"`
+	TickSize                    Decimal                    `json:"tickSize"`
+	ContractSize                Decimal                    `json:"contractSize"`
+	ContractValueTradePrecision Decimal                    `json:"contractValueTradePrecision"`
+	ImpactMidSize               Decimal                    `json:"impactMidSize"`
+	MaxPositionSize             Decimal                    `json:"maxPositionSize"`
+	IsTradeable                 bool                       `json:"tradeable"`
+	IsPostOnly                  bool                       `json:"postOnly"`
+	IsTradFi                    bool                       `json:"tradfi"`
+	IsMTF                       bool                       `json:"mtf"`
+	OpeningDate                 KrakenTime                 `json:"openingDate,omitempty"`
+	LastTradingTime             KrakenTime                 `json:"lastTradingTime,omitempty"`
+	MarginLevels                []KrakenFuturesMarginLevel `json:"marginLevels,omitempty"`
+	RetailMarginLevels          []KrakenFuturesMarginLevel `json:"retailMarginLevels,omitempty"`
+	FundingRateCoefficient      Decimal                    `json:"fundingRateCoefficient"`
+	MaxRelativeFundingRate      Decimal                    `json:"maxRelativeFundingRate"`
+	FeeScheduleUID              string                     `json:"feeScheduleUid,omitempty"`
+	ISIN                        string                     `json:"isin,omitempty"`
+
+	// Raw holds every field of the instrument as sent by Kraken, including
+	// fields not mapped above.
+	Raw map[string]any `json:"-"`
+}
+
+func (i *KrakenFuturesInstrument) UnmarshalJSON(data []byte) error {
+	type instrument KrakenFuturesInstrument
+	var typed instrument
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &typed.Raw); err != nil {
+		return err
+	}
+	*i = KrakenFuturesInstrument(typed)
+	return nil
+}
+
+// IsFlexible reports whether the instrument is a multi-collateral (flexible) future.
+func (i KrakenFuturesInstrument) IsFlexible() bool {
+	return i.Type == "flexible_futures"
+}
+
+// IsPerpetual reports whether the instrument is a future without expiry.
+func (i KrakenFuturesInstrument) IsPerpetual() bool {
+	return strings.Contains(i.Type, "futures") && i.LastTradingTime.IsZero()
+}
+
+// IsFixedMaturity reports whether the instrument is a future that expires at LastTradingTime.
+func (i KrakenFuturesInstrument) IsFixedMaturity() bool {
+	return !i.LastTradingTime.IsZero()
 }
 
 type KrakenFuturesInstrumentsResponse struct {
-	Instruments []map[string]any `json:"instruments,omitempty"`
-	Result      string           `json:"result"`
-	ServerTime  string           `json:"serverTime"`
-	Error       string           `json:"error,omitempty"`
-	Errors      []string         `json:"errors,omitempty"`
+	Instruments []KrakenFuturesInstrument `json:"instruments,omitempty"`
+	Result      string                    `json:"result"`
+	ServerTime  string                    `json:"serverTime"`
+	Error       string                    `json:"error,omitempty"`
+	Errors      []string                  `json:"errors,omitempty"`
 }
 
 type KrakenFuturesInstrumentStatus struct {
@@ -162,29 +452,29 @@ type KrakenFuturesInstrumentStatusResponse struct {
 
 type KrakenFuturesTickerInfo struct {
 	Symbol                   string         `json:"symbol"`
-	Last                     float64        `json:"last,omitempty"`
-	LastTime                 string         `json:"lastTime,omitempty"`
-	LastSize                 float64        `json:"lastSize,omitempty"`
+	Last                     Decimal        `json:"last,omitempty"`
+	LastTime                 KrakenTime     `json:"lastTime,omitempty"`
+	LastSize                 Decimal        `json:"lastSize,omitempty"`
 	Tag                      string         `json:"tag,omitempty"`
 	Pair                     string         `json:"pair,omitempty"`
-	MarkPrice                float64        `json:"markPrice,omitempty"`
-	BidPrice                 float64        `json:"bid,omitempty"`
-	BidSize                  float64        `json:"bidSize,omitempty"`
-	AskPrice                 float64        `json:"ask,omitempty"`
-	AskSize                  float64        `json:"askSize,omitempty"`
-	Vol24h                   float64        `json:"vol24h,omitempty"`
-	VolumeQuote              float64        `json:"volumeQuote,omitempty"`
-	OpenInterest             float64        `json:"openInterest,omitempty"`
-	Open24h                  float64        `json:"open24h,omitempty"`
-	High24h                  float64        `json:"high24h,omitempty"`
-	Low24h                   float64        `json:"low24h,omitempty"`
-	ExtrinsicValue           float64        `json:"extrinsicValue,omitempty"`
-	FundingRate              float64        `json:"fundingRate,omitempty"`
-	FundingRatePrediction    float64        `json:"fundingRatePrediction,omitempty"`
+	MarkPrice                Decimal        `json:"markPrice,omitempty"`
+	BidPrice                 Decimal        `json:"bid,omitempty"`
+	BidSize                  Decimal        `json:"bidSize,omitempty"`
+	AskPrice                 Decimal        `json:"ask,omitempty"`
+	AskSize                  Decimal        `json:"askSize,omitempty"`
+	Vol24h                   Decimal        `json:"vol24h,omitempty"`
+	VolumeQuote              Decimal        `json:"volumeQuote,omitempty"`
+	OpenInterest             Decimal        `json:"openInterest,omitempty"`
+	Open24h                  Decimal        `json:"open24h,omitempty"`
+	High24h                  Decimal        `json:"high24h,omitempty"`
+	Low24h                   Decimal        `json:"low24h,omitempty"`
+	ExtrinsicValue           Decimal        `json:"extrinsicValue,omitempty"`
+	FundingRate              Decimal        `json:"fundingRate,omitempty"`
+	FundingRatePrediction    Decimal        `json:"fundingRatePrediction,omitempty"`
 	IsSuspended              bool           `json:"suspended,omitempty"`
-	IndexPrice               float64        `json:"indexPrice,omitempty"`
+	IndexPrice               Decimal        `json:"indexPrice,omitempty"`
 	IsPostOnly               bool           `json:"postOnly,omitempty"`
-	PercentChange24h         float64        `json:"change24h,omitempty"`
+	PercentChange24h         Decimal        `json:"change24h,omitempty"`
 	Greeks                   map[string]any `json:"greeks,omitempty"`
 	IsUnderlyingMarketClosed bool           `json:"isUnderlyingMarketClosed,omitempty"`
 }
@@ -198,24 +488,24 @@ type KrakenFuturesTickerBySymbolResponse struct {
 }
 
 type KrakenFuturesTradeInfo struct {
-	TransactTime                  string  `json:"time"`
-	Price                         float64 `json:"price"`
-	Size                          float64 `json:"size,omitempty"`
-	Side                          string  `json:"side,omitempty"`
-	TradeId                       int64   `json:"trade_id,omitempty"`
-	Type                          string  `json:"type,omitempty"`
-	UID                           string  `json:"uid,omitempty"`
-	InstrumentIdentificationType  string  `json:"instrument_identification_type,omitempty"`
-	ISIN                          string  `json:"isin,omitempty"`
-	ExecutionVenue                string  `json:"execution_venue,omitempty"`
-	PriceNotation                 string  `json:"price_notation,omitempty"`
-	PriceCurrency                 string  `json:"price_currency,omitempty"`
-	NotionalAmount                float64 `json:"notional_amount,omitempty"`
-	NotionalCurrency              string  `json:"notional_currenct,omitempty"`
-	PublicationTime               string  `json:"publication_Type,omitempty"`
-	PublicationVenue              string  `json:"publication_venue,omitempty"`
-	TransactionIdentificationCode string  `json:"transaction_identification_code,omitempty"`
-	IsToBeCleared                 bool    `json:"to_be_cleared,omitempty"`
+	TransactTime                  KrakenTime             `json:"time"`
+	Price                         Decimal                `json:"price"`
+	Size                          Decimal                `json:"size,omitempty"`
+	Side                          KrakenSide             `json:"side,omitempty"`
+	TradeId                       int64                  `json:"trade_id,omitempty"`
+	Type                          KrakenFuturesTradeType `json:"type,omitempty"`
+	UID                           string                 `json:"uid,omitempty"`
+	InstrumentIdentificationType  string                 `json:"instrument_identification_type,omitempty"`
+	ISIN                          string                 `json:"isin,omitempty"`
+	ExecutionVenue                string                 `json:"execution_venue,omitempty"`
+	PriceNotation                 string                 `json:"price_notation,omitempty"`
+	PriceCurrency                 string                 `json:"price_currency,omitempty"`
+	NotionalAmount                Decimal                `json:"notional_amount,omitempty"`
+	NotionalCurrency              string                 `json:"notional_currenct,omitempty"`
+	PublicationTime               string                 `json:"publication_Type,omitempty"`
+	PublicationVenue              string                 `json:"publication_venue,omitempty"`
+	TransactionIdentificationCode string                 `json:"transaction_identification_code,omitempty"`
+	IsToBeCleared                 bool                   `json:"to_be_cleared,omitempty"`
 }
 
 type KrakenFuturesTradeHistoryResponse struct {
@@ -226,9 +516,22 @@ type KrakenFuturesTradeHistoryResponse struct {
 	Errors     []string                 `json:"errors,omitempty"`
 }
 
+// [<price>, <quantity>]
+type KrakenFuturesPriceLevel struct {
+	Price  Decimal
+	Volume Decimal
+}
+
+func (l *KrakenFuturesPriceLevel) UnmarshalJSON(data []byte) error {
+	d := newPositionalDecoder("KrakenFuturesPriceLevel", data, 2)
+	l.Price = d.decimal(0, "price")
+	l.Volume = d.decimal(1, "quantity")
+	return d.err
+}
+
 type KrakenFuturesOrderBook struct {
-	Bids [][]any `json:"bids"`
-	Asks [][]any `json:"asks"`
+	Bids []KrakenFuturesPriceLevel `json:"bids"`
+	Asks []KrakenFuturesPriceLevel `json:"asks"`
 }
 
 type KrakenFuturesOrderBookResponse struct {
@@ -240,9 +543,9 @@ type KrakenFuturesOrderBookResponse struct {
 }
 
 type KrakenFuturesFundingRate struct {
-	FundingRate         float64 `json:"fundingRate"`
-	RelativeFundingRate float64 `json:"relativeFundingRate"`
-	Timestamp           string  `json:"timestamp"`
+	FundingRate         Decimal    `json:"fundingRate"`
+	RelativeFundingRate Decimal    `json:"relativeFundingRate"`
+	Timestamp           KrakenTime `json:"timestamp"`
 }
 
 type KrakenFuturesFundingRateResponse struct {
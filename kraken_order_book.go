@@ -0,0 +1,154 @@
+package kraken
+
+// Order book helpers shared by KrakenSpotOrderBook and KrakenFuturesOrderBook.
+// Bids and asks are expected best level first, as Kraken sends them, for
+// CumulativeVolume; the other helpers do not depend on the order.
+
+var (
+	decimalHalf       = NewDecimal(5, 1)
+	decimalBasisPoint = NewDecimal(1, 4)
+	decimalOne        = NewDecimalFromInt(1)
+)
+
+type priceLevel interface {
+	levelPrice() Decimal
+	levelVolume() Decimal
+}
+
+func (l KrakenSpotPriceLevel) levelPrice() Decimal  { return l.Price }
+func (l KrakenSpotPriceLevel) levelVolume() Decimal { return l.Volume }
+
+func (l KrakenFuturesPriceLevel) levelPrice() Decimal  { return l.Price }
+func (l KrakenFuturesPriceLevel) levelVolume() Decimal { return l.Volume }
+
+// bestLevel returns the level with the highest price if highest is set, the
+// lowest otherwise.
+func bestLevel[L priceLevel](levels []L, highest bool) (L, bool) {
+	var best L
+	if len(levels) == 0 {
+		return best, false
+	}
+
+	best = levels[0]
+	for _, level := range levels[1:] {
+		cmp := level.levelPrice().Cmp(best.levelPrice())
+		if (highest && cmp > 0) || (!highest && cmp < 0) {
+			best = level
+		}
+	}
+	return best, true
+}
+
+func midPrice[L priceLevel](bids, asks []L) (Decimal, bool) {
+	bid, okBid := bestLevel(bids, true)
+	ask, okAsk := bestLevel(asks, false)
+	if !okBid || !okAsk {
+		return Decimal{}, false
+	}
+	return bid.levelPrice().Add(ask.levelPrice()).Mul(decimalHalf), true
+}
+
+func spread[L priceLevel](bids, asks []L) (Decimal, bool) {
+	bid, okBid := bestLevel(bids, true)
+	ask, okAsk := bestLevel(asks, false)
+	if !okBid || !okAsk {
+		return Decimal{}, false
+	}
+	return ask.levelPrice().Sub(bid.levelPrice()), true
+}
+
+// depthWithin sums the volume of the bids priced at or above, and the asks
+// priced at or below, mid price -/+ bps basis points.
+func depthWithin[L priceLevel](bids, asks []L, bps Decimal) (bidVolume, askVolume Decimal) {
+	mid, ok := midPrice(bids, asks)
+	if !ok {
+		return Decimal{}, Decimal{}
+	}
+
+	offset := bps.Mul(decimalBasisPoint)
+	low := mid.Mul(decimalOne.Sub(offset))
+	high := mid.Mul(decimalOne.Add(offset))
+
+	for _, level := range bids {
+		if level.levelPrice().Cmp(low) >= 0 {
+			bidVolume = bidVolume.Add(level.levelVolume())
+		}
+	}
+	for _, level := range asks {
+		if level.levelPrice().Cmp(high) <= 0 {
+			askVolume = askVolume.Add(level.levelVolume())
+		}
+	}
+	return bidVolume, askVolume
+}
+
+// cumulativeVolume returns the running total of volume down the levels.
+func cumulativeVolume[L priceLevel](levels []L) []Decimal {
+	out := make([]Decimal, len(levels))
+	var total Decimal
+	for i, level := range levels {
+		total = total.Add(level.levelVolume())
+		out[i] = total
+	}
+	return out
+}
+
+// BestBid returns the bid with the highest price, if any.
+func (b KrakenSpotOrderBook) BestBid() (KrakenSpotPriceLevel, bool) {
+	return bestLevel(b.Bids, true)
+}
+
+// BestAsk returns the ask with the lowest price, if any.
+func (b KrakenSpotOrderBook) BestAsk() (KrakenSpotPriceLevel, bool) {
+	return bestLevel(b.Asks, false)
+}
+
+// Mid returns the price halfway between the best bid and the best ask.
+func (b KrakenSpotOrderBook) Mid() (Decimal, bool) {
+	return midPrice(b.Bids, b.Asks)
+}
+
+// Spread returns the best ask price minus the best bid price.
+func (b KrakenSpotOrderBook) Spread() (Decimal, bool) {
+	return spread(b.Bids, b.Asks)
+}
+
+// DepthWithin returns the bid and ask volume within bps basis points of the mid price.
+func (b KrakenSpotOrderBook) DepthWithin(bps Decimal) (bidVolume, askVolume Decimal) {
+	return depthWithin(b.Bids, b.Asks, bps)
+}
+
+// CumulativeVolume returns the running total of the volume at each bid and ask level.
+func (b KrakenSpotOrderBook) CumulativeVolume() (bids, asks []Decimal) {
+	return cumulativeVolume(b.Bids), cumulativeVolume(b.Asks)
+}
+
+// BestBid returns the bid with the highest price, if any.
+func (b KrakenFuturesOrderBook) BestBid() (KrakenFuturesPriceLevel, bool) {
+	return bestLevel(b.Bids, true)
+}
+
+// BestAsk returns the ask with the lowest price, if any.
+func (b KrakenFuturesOrderBook) BestAsk() (KrakenFuturesPriceLevel, bool) {
+	return bestLevel(b.Asks, false)
+}
+
+// Mid returns the price halfway between the best bid and the best ask.
+func (b KrakenFuturesOrderBook) Mid() (Decimal, bool) {
+	return midPrice(b.Bids, b.Asks)
+}
+
+// Spread returns the best ask price minus the best bid price.
+func (b KrakenFuturesOrderBook) Spread() (Decimal, bool) {
+	return spread(b.Bids, b.Asks)
+}
+
+// DepthWithin returns the bid and ask volume within bps basis points of the mid price.
+func (b KrakenFuturesOrderBook) DepthWithin(bps Decimal) (bidVolume, askVolume Decimal) {
+	return depthWithin(b.Bids, b.Asks, bps)
+}
+
+// CumulativeVolume returns the running total of the volume at each bid and ask level.
+func (b KrakenFuturesOrderBook) CumulativeVolume() (bids, asks []Decimal) {
+	return cumulativeVolume(b.Bids), cumulativeVolume(b.Asks)
+}
@@ -0,0 +1,262 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by a fail fast RateLimiter when a call would
+// exceed the client side budget.
+var ErrRateLimited = errors.New("kraken: client rate limit reached")
+
+// RateLimiter paces requests before they are sent. endpoint is the request
+// path relative to the client's base URL, e.g. "/public/Ticker" or
+// "/derivatives/api/v3/sendorder". Wait blocks until the call may proceed or
+// returns an error if it may not.
+type RateLimiter interface {
+	Wait(ctx context.Context, endpoint string) error
+}
+
+// WithRateLimiter sets the RateLimiter consulted before every request,
+// including retries.
+func WithRateLimiter(limiter RateLimiter) ClientOption {
+	return func(o *clientOptions) {
+		o.limiter = limiter
+	}
+}
+
+// callCounter models Kraken's decaying call counters: every call adds its cost
+// to the counter, the counter decays linearly over time and must not exceed max.
+type callCounter struct {
+	max   float64
+	decay float64 // per second
+	level float64
+	last  time.Time
+}
+
+// reserve adds cost to the counter and returns how long the caller must wait
+// for the counter to have decayed back under max. With failFast nothing is
+// reserved and ok is false if the caller would have to wait.
+func (c *callCounter) reserve(now time.Time, cost float64, failFast bool) (wait time.Duration, ok bool) {
+	if !c.last.IsZero() {
+		c.level = max(0, c.level-now.Sub(c.last).Seconds()*c.decay)
+	}
+	c.last = now
+
+	excess := c.level + cost - c.max
+	if excess > 0 {
+		if failFast || c.decay <= 0 {
+			return 0, false
+		}
+		wait = time.Duration(excess / c.decay * float64(time.Second))
+	}
+	c.level += cost
+	return wait, true
+}
+
+// cancel gives back the cost of a reservation that was not used.
+func (c *callCounter) cancel(cost float64) {
+	c.level = max(0, c.level-cost)
+}
+
+// waitCounter reserves cost on counter, guarded by mu, and waits for it.
+func waitCounter(ctx context.Context, mu *sync.Mutex, counter *callCounter, cost float64, failFast bool) error {
+	if cost <= 0 {
+		return nil
+	}
+
+	mu.Lock()
+	wait, ok := counter.reserve(time.Now(), cost, failFast)
+	mu.Unlock()
+	if !ok {
+		return ErrRateLimited
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, wait); err != nil {
+		mu.Lock()
+		counter.cancel(cost)
+		mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// KrakenSpotTier is the verification tier of a spot account, which determines
+// its private API call counter.
+type KrakenSpotTier int
+
+const (
+	KrakenSpotTierStarter KrakenSpotTier = iota
+	KrakenSpotTierIntermediate
+	KrakenSpotTierPro
+)
+
+// KrakenSpotRateLimiterConfig configures a KrakenSpotRateLimiter.
+type KrakenSpotRateLimiterConfig struct {
+	// MaxCounter and DecayPerSecond describe the private API call counter.
+	MaxCounter     float64
+	DecayPerSecond float64
+	// PublicMaxCounter and PublicDecayPerSecond describe the counter applied
+	// to public endpoints, which Kraken limits per IP address.
+	PublicMaxCounter     float64
+	PublicDecayPerSecond float64
+	// FailFast returns ErrRateLimited instead of waiting.
+	FailFast bool
+}
+
+// KrakenSpotTierConfig returns the documented call counter limits of a tier,
+// with public endpoints limited to one call per second.
+func KrakenSpotTierConfig(tier KrakenSpotTier) KrakenSpotRateLimiterConfig {
+	config := KrakenSpotRateLimiterConfig{
+		PublicMaxCounter:     1,
+		PublicDecayPerSecond: 1,
+	}
+	switch tier {
+	case KrakenSpotTierIntermediate:
+		config.MaxCounter, config.DecayPerSecond = 20, 0.5
+	case KrakenSpotTierPro:
+		config.MaxCounter, config.DecayPerSecond = 20, 1
+	default:
+		config.MaxCounter, config.DecayPerSecond = 15, 0.33
+	}
+	return config
+}
+
+// krakenSpotEndpointCosts lists the private endpoints whose cost differs from
+// the default of 1. Order placement and cancellation are governed by the
+// separate per-pair trading limits and do not count.
+var krakenSpotEndpointCosts = map[string]float64{
+	"/private/Ledgers":              2,
+	"/private/QueryLedgers":         2,
+	"/private/TradesHistory":        2,
+	"/private/QueryTrades":          2,
+	"/private/AddOrder":             0,
+	"/private/AddOrderBatch":        0,
+	"/private/AmendOrder":           0,
+	"/private/EditOrder":            0,
+	"/private/CancelOrder":          0,
+	"/private/CancelOrderBatch":     0,
+	"/private/CancelAll":            0,
+	"/private/CancelAllOrdersAfter": 0,
+}
+
+// KrakenSpotRateLimiter models the spot API call counters.
+type KrakenSpotRateLimiter struct {
+	mu       sync.Mutex
+	private  callCounter
+	public   callCounter
+	failFast bool
+}
+
+func NewKrakenSpotRateLimiter(config KrakenSpotRateLimiterConfig) *KrakenSpotRateLimiter {
+	return &KrakenSpotRateLimiter{
+		private:  callCounter{max: config.MaxCounter, decay: config.DecayPerSecond},
+		public:   callCounter{max: config.PublicMaxCounter, decay: config.PublicDecayPerSecond},
+		failFast: config.FailFast,
+	}
+}
+
+func (l *KrakenSpotRateLimiter) Wait(ctx context.Context, endpoint string) error {
+	if strings.HasPrefix(endpoint, "/public/") {
+		return waitCounter(ctx, &l.mu, &l.public, 1, l.failFast)
+	}
+
+	cost, ok := krakenSpotEndpointCosts[endpoint]
+	if !ok {
+		cost = 1
+	}
+	return waitCounter(ctx, &l.mu, &l.private, cost, l.failFast)
+}
+
+// KrakenFuturesRateLimiterConfig configures a KrakenFuturesRateLimiter.
+type KrakenFuturesRateLimiterConfig struct {
+	// Budget is the cost that may be spent on /derivatives endpoints per Interval.
+	Budget   float64
+	Interval time.Duration
+	// HistoryBudget is the cost that may be spent on /api/history endpoints per
+	// HistoryInterval. Intervals that are not positive default to those of
+	// DefaultKrakenFuturesRateLimiterConfig.
+	HistoryBudget   float64
+	HistoryInterval time.Duration
+	// Costs maps endpoints to their cost. Endpoints not listed cost DefaultCost.
+	Costs       map[string]float64
+	DefaultCost float64
+	// FailFast returns ErrRateLimited instead of waiting.
+	FailFast bool
+}
+
+// DefaultKrakenFuturesRateLimiterConfig returns the documented futures
+// budgets and endpoint costs.
+func DefaultKrakenFuturesRateLimiterConfig() KrakenFuturesRateLimiterConfig {
+	return KrakenFuturesRateLimiterConfig{
+		Budget:          500,
+		Interval:        10 * time.Second,
+		HistoryBudget:   100,
+		HistoryInterval: 10 * time.Minute,
+		Costs: map[string]float64{
+			"/derivatives/api/v3/sendorder":            10,
+			"/derivatives/api/v3/editorder":            10,
+			"/derivatives/api/v3/cancelorder":          10,
+			"/derivatives/api/v3/batchorder":           10,
+			"/derivatives/api/v3/cancelallorders":      25,
+			"/derivatives/api/v3/cancelallordersafter": 25,
+			"/derivatives/api/v3/accounts":             2,
+			"/derivatives/api/v3/openpositions":        2,
+			"/derivatives/api/v3/fills":                2,
+			"/derivatives/api/v3/openorders":           2,
+			"/derivatives/api/v3/orders/status":        1,
+			"/derivatives/api/v3/transfer":             10,
+			"/derivatives/api/v3/leveragepreferences":  2,
+			"/derivatives/api/v3/pnlpreferences":       2,
+		},
+		DefaultCost: 1,
+	}
+}
+
+// KrakenFuturesRateLimiter models the futures cost based budgets.
+type KrakenFuturesRateLimiter struct {
+	mu          sync.Mutex
+	derivatives callCounter
+	history     callCounter
+	costs       map[string]float64
+	defaultCost float64
+	failFast    bool
+}
+
+func NewKrakenFuturesRateLimiter(config KrakenFuturesRateLimiterConfig) *KrakenFuturesRateLimiter {
+	// a zero interval would make the decay infinite or NaN, and the counter
+	// would stop limiting
+	defaults := DefaultKrakenFuturesRateLimiterConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.HistoryInterval <= 0 {
+		config.HistoryInterval = defaults.HistoryInterval
+	}
+
+	return &KrakenFuturesRateLimiter{
+		derivatives: callCounter{max: config.Budget, decay: config.Budget / config.Interval.Seconds()},
+		history:     callCounter{max: config.HistoryBudget, decay: config.HistoryBudget / config.HistoryInterval.Seconds()},
+		costs:       config.Costs,
+		defaultCost: config.DefaultCost,
+		failFast:    config.FailFast,
+	}
+}
+
+func (l *KrakenFuturesRateLimiter) Wait(ctx context.Context, endpoint string) error {
+	cost, ok := l.costs[endpoint]
+	if !ok {
+		cost = l.defaultCost
+	}
+
+	if strings.HasPrefix(endpoint, "/api/history/") {
+		return waitCounter(ctx, &l.mu, &l.history, cost, l.failFast)
+	}
+	return waitCounter(ctx, &l.mu, &l.derivatives, cost, l.failFast)
+}
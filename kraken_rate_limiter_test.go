@@ -0,0 +1,125 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCallCounterReserve(t *testing.T) {
+	start := time.Date(2023, 7, 6, 18, 50, 48, 0, time.UTC)
+	c := callCounter{max: 15, decay: 0.5}
+	for _, tt := range []struct {
+		name  string
+		after time.Duration
+		cost  float64
+		wait  time.Duration
+		level float64
+		ok    bool
+	}{
+		{"fills the counter", 0, 15, 0, 15, true},
+		{"waits for the excess to decay", 0, 1, 2 * time.Second, 16, true},
+		{"decays linearly", 4 * time.Second, 1, 0, 15, true},
+		{"never decays below zero", time.Hour, 2, 0, 2, true},
+	} {
+		wait, ok := c.reserve(start.Add(tt.after), tt.cost, false)
+		if wait != tt.wait || ok != tt.ok || math.Abs(c.level-tt.level) > 1e-9 {
+			t.Errorf("%s: reserve() = %v, %t, level %g, want %v, %t, level %g", tt.name, wait, ok, c.level, tt.wait, tt.ok, tt.level)
+		}
+		start = start.Add(tt.after)
+	}
+
+	c.cancel(5)
+	if c.level != 0 {
+		t.Errorf("cancel() left level %g, want 0", c.level)
+	}
+}
+
+func TestCallCounterReserveFailFast(t *testing.T) {
+	now := time.Date(2023, 7, 6, 18, 50, 48, 0, time.UTC)
+	c := callCounter{max: 1, decay: 1}
+	if _, ok := c.reserve(now, 1, true); !ok {
+		t.Fatal("first reserve() failed")
+	}
+	if _, ok := c.reserve(now.Add(500*time.Millisecond), 1, true); ok {
+		t.Error("reserve() over the maximum succeeded")
+	}
+	if math.Abs(c.level-0.5) > 1e-9 {
+		t.Errorf("failed reserve() left level %g, want 0.5", c.level)
+	}
+	if wait, ok := c.reserve(now.Add(time.Second), 1, true); !ok || wait != 0 {
+		t.Errorf("reserve() after decay = %v, %t, want 0, true", wait, ok)
+	}
+
+	// a counter that never decays can only fail once full
+	c = callCounter{max: 1}
+	c.reserve(now, 1, false)
+	if _, ok := c.reserve(now, 1, false); ok {
+		t.Error("reserve() on a full counter without decay succeeded")
+	}
+}
+
+func TestWaitCounterRefundsOnCancel(t *testing.T) {
+	var mu sync.Mutex
+	c := callCounter{max: 1, decay: 0.001, level: 1, last: time.Now()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := waitCounter(ctx, &mu, &c, 1, false); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("waitCounter() error = %v, want context.DeadlineExceeded", err)
+	}
+	if c.level > 1 {
+		t.Errorf("level after the cancelled wait = %g, want the reservation refunded", c.level)
+	}
+
+	if err := waitCounter(ctx, &mu, &c, 1, true); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("fail fast waitCounter() error = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestKrakenSpotRateLimiterCosts(t *testing.T) {
+	l := NewKrakenSpotRateLimiter(KrakenSpotRateLimiterConfig{MaxCounter: 100, PublicMaxCounter: 100})
+	ctx := context.Background()
+	for _, endpoint := range []string{"/private/Balance", "/private/Ledgers", "/private/QueryTrades", "/private/AddOrder", "/private/CancelOrder", "/public/Ticker", "/public/Depth"} {
+		if err := l.Wait(ctx, endpoint); err != nil {
+			t.Fatalf("Wait(%s) error = %v", endpoint, err)
+		}
+	}
+	if l.private.level != 5 || l.public.level != 2 {
+		t.Errorf("levels private %g, public %g, want 5 and 2", l.private.level, l.public.level)
+	}
+
+	l = NewKrakenSpotRateLimiter(KrakenSpotRateLimiterConfig{MaxCounter: 15, DecayPerSecond: 0.33, PublicMaxCounter: 1, PublicDecayPerSecond: 1, FailFast: true})
+	if err := l.Wait(ctx, "/public/Ticker"); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if err := l.Wait(ctx, "/public/Ticker"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("second Wait() error = %v, want ErrRateLimited", err)
+	}
+	if err := l.Wait(ctx, "/private/Balance"); err != nil {
+		t.Errorf("Wait() on the private counter error = %v", err)
+	}
+}
+
+func TestKrakenFuturesRateLimiter(t *testing.T) {
+	config := DefaultKrakenFuturesRateLimiterConfig()
+	l := NewKrakenFuturesRateLimiter(config)
+	ctx := context.Background()
+	for _, endpoint := range []string{"/derivatives/api/v3/sendorder", "/derivatives/api/v3/cancelallorders", "/derivatives/api/v3/tickers", "/api/history/v2/executions"} {
+		if err := l.Wait(ctx, endpoint); err != nil {
+			t.Fatalf("Wait(%s) error = %v", endpoint, err)
+		}
+	}
+	if math.Abs(l.derivatives.level-36) > 0.01 || math.Abs(l.history.level-1) > 0.01 {
+		t.Errorf("levels derivatives %g, history %g, want 36 and 1", l.derivatives.level, l.history.level)
+	}
+
+	config.Interval, config.HistoryInterval = 0, -time.Second
+	l = NewKrakenFuturesRateLimiter(config)
+	if l.derivatives.decay != 50 || math.Abs(l.history.decay-100.0/600) > 1e-9 {
+		t.Errorf("decays with zero intervals = %g, %g, want the default intervals' 50 and %g", l.derivatives.decay, l.history.decay, 100.0/600)
+	}
+}
@@ -0,0 +1,132 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the clients retry requests that failed for
+// transient reasons: network errors, 5xx and 429 responses and the Kraken
+// errors EService:Unavailable and EService:Busy.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. A Retry-After header asking
+	// for a longer delay is still honoured.
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after each attempt. Defaults to 2.
+	Multiplier float64
+	// RetryNonIdempotent allows retrying private calls that change state, such
+	// as placing or cancelling orders. Leave it off unless duplicate requests
+	// are harmless to you.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a policy making up to 4 attempts with jittered
+// backoff between 250ms and 10s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+	}
+}
+
+// WithRetryPolicy enables retries of transient failures. Clients do not retry
+// unless this option is given.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retry = policy
+	}
+}
+
+// backoff returns the delay before the retry following the given attempt,
+// jittered over the upper half of the exponential delay.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		delay *= multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	half := int64(delay / 2)
+	if half <= 0 {
+		return time.Duration(delay)
+	}
+	return time.Duration(half + rand.Int64N(half+1))
+}
+
+// isRetryable reports whether err is a transient failure worth retrying.
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+
+	var krakenErr *KrakenError
+	if errors.As(err, &krakenErr) {
+		return errors.Is(err, ErrServiceUnavailable) || errors.Is(err, ErrServiceBusy)
+	}
+
+	// anything else failed before a response was received
+	return true
+}
+
+// retryAfter returns the delay requested by a Retry-After header, if any.
+func retryAfter(err error) time.Duration {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Header == nil {
+		return 0
+	}
+
+	value := httpErr.Header.Get("Retry-After")
+	if len(value) == 0 {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// sleep waits for delay unless ctx is done first or its deadline would pass
+// before the delay elapses.
+func sleep(ctx context.Context, delay time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,242 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const serverTimeBody = `{"error":[],"result":{"unixtime":1688669448,"rfc1123":"Thu, 06 Jul 23 18:50:48 +0000"}}`
+
+var fastRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Multiplier:     2,
+}
+
+// newAttemptServer serves the response returned by respond for each attempt,
+// counting attempts from 1.
+func newAttemptServer(t *testing.T, respond func(attempt int32, w http.ResponseWriter)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond(attempts.Add(1), w)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &attempts
+}
+
+func TestRetryServiceUnavailableThenSuccess(t *testing.T) {
+	srv, attempts := newAttemptServer(t, func(attempt int32, w http.ResponseWriter) {
+		if attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(serverTimeBody))
+	})
+
+	c := NewKrakenSpotHttpClient(WithBaseURL(srv.URL), WithRetryPolicy(fastRetryPolicy))
+	serverTime, err := c.GetServerTime()
+	if err != nil {
+		t.Fatalf("GetServerTime() error = %v", err)
+	}
+	if serverTime.UnixTime != 1688669448 {
+		t.Errorf("UnixTime = %d, want 1688669448", serverTime.UnixTime)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	srv, attempts := newAttemptServer(t, func(attempt int32, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	c := NewKrakenSpotHttpClient(WithBaseURL(srv.URL), WithRetryPolicy(fastRetryPolicy))
+	_, err := c.GetServerTime()
+	if !errors.Is(err, ErrServiceUnavailable) {
+		t.Fatalf("GetServerTime() error = %v, want ErrServiceUnavailable", err)
+	}
+	if got := attempts.Load(); got != 4 {
+		t.Errorf("attempts = %d, want 4", got)
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	srv, attempts := newAttemptServer(t, func(attempt int32, w http.ResponseWriter) {
+		if attempt == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(serverTimeBody))
+	})
+
+	c := NewKrakenSpotHttpClient(WithBaseURL(srv.URL), WithRetryPolicy(fastRetryPolicy))
+	start := time.Now()
+	if _, err := c.GetServerTime(); err != nil {
+		t.Fatalf("GetServerTime() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s of Retry-After", elapsed)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := func(value string) *HTTPError {
+		return &HTTPError{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {value}}}
+	}
+
+	if got := retryAfter(header("3")); got != 3*time.Second {
+		t.Errorf("retryAfter(3) = %v, want 3s", got)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := retryAfter(header(date)); got <= 50*time.Second || got > time.Minute {
+		t.Errorf("retryAfter(%s) = %v, want about 1m", date, got)
+	}
+	if got := retryAfter(header("soon")); got != 0 {
+		t.Errorf("retryAfter(soon) = %v, want 0", got)
+	}
+	if got := retryAfter(errors.New("network")); got != 0 {
+		t.Errorf("retryAfter(non HTTP error) = %v, want 0", got)
+	}
+}
+
+func TestRetryKrakenErrorInSuccessfulResponse(t *testing.T) {
+	srv, attempts := newAttemptServer(t, func(attempt int32, w http.ResponseWriter) {
+		if attempt == 1 {
+			w.Write([]byte(`{"error":["EService:Unavailable"],"result":{}}`))
+			return
+		}
+		w.Write([]byte(serverTimeBody))
+	})
+
+	c := NewKrakenSpotHttpClient(WithBaseURL(srv.URL), WithRetryPolicy(fastRetryPolicy))
+	if _, err := c.GetServerTime(); err != nil {
+		t.Fatalf("GetServerTime() error = %v", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestRetryDoesNotRetryPermanentKrakenError(t *testing.T) {
+	srv, attempts := newAttemptServer(t, func(attempt int32, w http.ResponseWriter) {
+		w.Write([]byte(`{"error":["EGeneral:Invalid arguments"],"result":{}}`))
+	})
+
+	c := NewKrakenSpotHttpClient(WithBaseURL(srv.URL), WithRetryPolicy(fastRetryPolicy))
+	if _, err := c.GetServerTime(); !errors.Is(err, ErrInvalidArguments) {
+		t.Fatalf("GetServerTime() error = %v, want ErrInvalidArguments", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryStopsWhenDeadlineIsShorterThanBackoff(t *testing.T) {
+	srv, attempts := newAttemptServer(t, func(attempt int32, w http.ResponseWriter) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	policy := fastRetryPolicy
+	policy.InitialBackoff = 10 * time.Second
+	policy.MaxBackoff = 10 * time.Second
+	c := NewKrakenSpotHttpClient(WithBaseURL(srv.URL), WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.GetServerTimeWithContext(ctx)
+	if !errors.Is(err, ErrServiceUnavailable) {
+		t.Fatalf("GetServerTimeWithContext() error = %v, want ErrServiceUnavailable", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v, want before the deadline", elapsed)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryNonIdempotentRequestSentOnce(t *testing.T) {
+	for _, tt := range []struct {
+		name               string
+		retryNonIdempotent bool
+		want               int32
+	}{
+		{"default", false, 1},
+		{"RetryNonIdempotent", true, 4},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, attempts := newAttemptServer(t, func(attempt int32, w http.ResponseWriter) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			})
+
+			policy := fastRetryPolicy
+			policy.RetryNonIdempotent = tt.retryNonIdempotent
+			c := NewKrakenSpotHttpClient(WithBaseURL(srv.URL), WithRetryPolicy(policy), WithCredentials("key", "c2VjcmV0"))
+			if _, err := c.post(context.Background(), "/private/AddOrder", nil, false); !errors.Is(err, ErrServiceUnavailable) {
+				t.Fatalf("post() error = %v, want ErrServiceUnavailable", err)
+			}
+			if got := attempts.Load(); got != tt.want {
+				t.Errorf("attempts = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	for _, tt := range []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{10, time.Second},
+	} {
+		for range 20 {
+			if got := policy.backoff(tt.attempt); got < tt.max/2 || got > tt.max {
+				t.Errorf("backoff(%d) = %v, want within [%v, %v]", tt.attempt, got, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestRetryDoesNotRetryRequestConstructionErrors(t *testing.T) {
+	srv, attempts := newAttemptServer(t, func(attempt int32, w http.ResponseWriter) {
+		w.Write([]byte(`{"error":[],"result":{}}`))
+	})
+
+	errOTP := errors.New("otp unavailable")
+	var otpCalls atomic.Int32
+	c := NewKrakenSpotHttpClient(
+		WithBaseURL(srv.URL),
+		WithRetryPolicy(fastRetryPolicy),
+		WithCredentials("key", "c2VjcmV0"),
+		WithOTP(func() (string, error) {
+			otpCalls.Add(1)
+			return "", errOTP
+		}),
+	)
+	if _, err := c.post(context.Background(), "/private/Balance", nil, true); !errors.Is(err, errOTP) {
+		t.Fatalf("post() error = %v, want the OTP error", err)
+	}
+	if got := otpCalls.Load(); got != 1 {
+		t.Errorf("OTP calls = %d, want 1", got)
+	}
+	if got := attempts.Load(); got != 0 {
+		t.Errorf("requests = %d, want 0", got)
+	}
+}
@@ -0,0 +1,288 @@
+package kraken
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoCredentials is returned by private endpoints of a client created
+// without WithCredentials.
+var ErrNoCredentials = errors.New("kraken: private endpoint requires credentials")
+
+// WithCredentials sets the API key and the base64 encoded API secret used to
+// sign requests to the spot private endpoints.
+func WithCredentials(apiKey, apiSecret string) ClientOption {
+	return func(o *clientOptions) {
+		o.apiKey = apiKey
+		o.apiSecret = apiSecret
+	}
+}
+
+// WithOTP sets the provider of the one time password sent with private
+// requests when the API key is protected by two-factor authentication. It is
+// called for every request, so it can return a static password or the current
+// TOTP code.
+func WithOTP(otp func() (string, error)) ClientOption {
+	return func(o *clientOptions) {
+		o.otp = otp
+	}
+}
+
+// WithNonceGenerator sets the source of nonces for private requests. It
+// defaults to NewTimeNonceGenerator.
+func WithNonceGenerator(generator NonceGenerator) ClientOption {
+	return func(o *clientOptions) {
+		o.nonce = generator
+	}
+}
+
+// NonceGenerator returns strictly increasing nonces for private requests.
+//
+// Kraken rejects a nonce that is not greater than the last one it accepted
+// for the API key. Requests signed concurrently may reach Kraken out of order,
+// so keys used concurrently should be given a nonce window in their settings.
+// ctx is the context of the request being signed; generators that wait should
+// give up when it is done.
+type NonceGenerator interface {
+	Nonce(ctx context.Context) (uint64, error)
+}
+
+type timeNonceGenerator struct {
+	last atomic.Uint64
+}
+
+// NewTimeNonceGenerator returns a NonceGenerator based on the current Unix
+// time in milliseconds that stays strictly increasing when called from
+// several goroutines, or several times within a millisecond.
+//
+// Processes sharing an API key should share a NewFileNonceGenerator instead.
+func NewTimeNonceGenerator() NonceGenerator {
+	return &timeNonceGenerator{}
+}
+
+func (g *timeNonceGenerator) Nonce(ctx context.Context) (uint64, error) {
+	for {
+		last := g.last.Load()
+		next := max(uint64(time.Now().UnixMilli()), last+1)
+		if g.last.CompareAndSwap(last, next) {
+			return next, nil
+		}
+	}
+}
+
+type fileNonceGenerator struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileNonceGenerator returns a NonceGenerator whose last nonce is kept in
+// the file at path, so that processes sharing an API key through the same
+// file never reuse a nonce. Access to the file is serialized with an OS file
+// lock, which is released by the OS if a process dies holding it. Nonces
+// follow the Unix time in milliseconds like NewTimeNonceGenerator.
+//
+// File locks are supported on Windows and on Linux, macOS, the BSDs and
+// illumos; elsewhere Nonce returns errors.ErrUnsupported.
+func NewFileNonceGenerator(path string) NonceGenerator {
+	return &fileNonceGenerator{path: path}
+}
+
+func (g *fileNonceGenerator) Nonce(ctx context.Context) (uint64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	f, err := os.OpenFile(g.path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if err := lockNonceFile(ctx, f); err != nil {
+		return 0, err
+	}
+	defer unlockFile(f)
+
+	var last uint64
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, err
+	}
+	if text := strings.TrimSpace(string(data)); len(text) > 0 {
+		last, err = strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("kraken: invalid nonce file %s: %w", g.path, err)
+		}
+	}
+
+	// The nonce never gets shorter, so writing it over the old one before
+	// truncating never leaves a smaller nonce in the file.
+	next := max(uint64(time.Now().UnixMilli()), last+1)
+	text := strconv.FormatUint(next, 10)
+	if _, err := f.WriteAt([]byte(text), 0); err != nil {
+		return 0, err
+	}
+	if err := f.Truncate(int64(len(text))); err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// lockNonceFile takes an exclusive lock on f, polling while another process
+// holds it until ctx is done.
+func lockNonceFile(ctx context.Context, f *os.File) error {
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			return fmt.Errorf("kraken: locking nonce file %s: %w", f.Name(), err)
+		}
+		if locked {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+// signKrakenSpotRequest returns the API-Sign header of a private request:
+// HMAC-SHA512 of the URI path followed by SHA256(nonce + POST data), keyed by
+// the decoded API secret.
+func signKrakenSpotRequest(secret []byte, path string, nonce string, postData []byte) string {
+	sha := sha256.New()
+	sha.Write([]byte(nonce))
+	sha.Write(postData)
+
+	mac := hmac.New(sha512.New, secret)
+	mac.Write([]byte(path))
+	mac.Write(sha.Sum(nil))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// krakenSpotCredentials signs private requests of a KrakenSpotHttpClient.
+type krakenSpotCredentials struct {
+	apiKey string
+	secret []byte
+	// err is the error decoding the secret, reported by every private call
+	err   error
+	otp   func() (string, error)
+	nonce NonceGenerator
+}
+
+func newKrakenSpotCredentials(o clientOptions) *krakenSpotCredentials {
+	if len(o.apiKey) == 0 {
+		return nil
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(o.apiSecret)
+	if err != nil {
+		err = fmt.Errorf("kraken: decoding API secret: %w", err)
+	}
+	nonce := o.nonce
+	if nonce == nil {
+		nonce = NewTimeNonceGenerator()
+	}
+	return &krakenSpotCredentials{
+		apiKey: o.apiKey,
+		secret: secret,
+		err:    err,
+		otp:    o.otp,
+		nonce:  nonce,
+	}
+}
+
+// post sends a signed request to a private endpoint with params form encoded.
+// A fresh nonce is signed for every attempt. Requests that change state must
+// pass idempotent as false so they are not retried unless the retry policy
+// allows it.
+func (c *KrakenSpotHttpClient) post(ctx context.Context, endpoint string, params url.Values, idempotent bool) ([]byte, error) {
+	encode := func(nonce uint64, otp string) ([]byte, error) {
+		form := url.Values{}
+		for key, values := range params {
+			form[key] = values
+		}
+		form.Set("nonce", strconv.FormatUint(nonce, 10))
+		if len(otp) > 0 {
+			form.Set("otp", otp)
+		}
+		return []byte(form.Encode()), nil
+	}
+	return c.signedPost(ctx, endpoint, "application/x-www-form-urlencoded", encode, idempotent)
+}
+
+// postJSON is like post for the endpoints that take a JSON body, such as the
+// batch order endpoints. The nonce and one time password are added to payload.
+func (c *KrakenSpotHttpClient) postJSON(ctx context.Context, endpoint string, payload map[string]any, idempotent bool) ([]byte, error) {
+	encode := func(nonce uint64, otp string) ([]byte, error) {
+		body := make(map[string]any, len(payload)+2)
+		for key, value := range payload {
+			body[key] = value
+		}
+		body["nonce"] = nonce
+		if len(otp) > 0 {
+			body["otp"] = otp
+		}
+		return json.Marshal(body)
+	}
+	return c.signedPost(ctx, endpoint, "application/json", encode, idempotent)
+}
+
+// signedPost sends the body returned by encode for a fresh nonce on every
+// attempt, signed with the client's credentials.
+func (c *KrakenSpotHttpClient) signedPost(ctx context.Context, endpoint string, contentType string, encode func(nonce uint64, otp string) ([]byte, error), idempotent bool) ([]byte, error) {
+	if c.credentials == nil {
+		return nil, ErrNoCredentials
+	}
+	if c.credentials.err != nil {
+		return nil, c.credentials.err
+	}
+
+	requestURL := c.baseURL + endpoint
+	newRequest := func() (*http.Request, error) {
+		nonce, err := c.credentials.nonce.Nonce(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var otp string
+		if c.credentials.otp != nil {
+			otp, err = c.credentials.otp()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		postData, err := encode(nonce, otp)
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(postData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("API-Key", c.credentials.apiKey)
+		req.Header.Set("API-Sign", signKrakenSpotRequest(c.credentials.secret, req.URL.Path, strconv.FormatUint(nonce, 10), postData))
+		return req, nil
+	}
+	return c.transport.do(ctx, endpoint, newRequest, idempotent)
+}
@@ -0,0 +1,185 @@
+package kraken
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSignKrakenSpotRequest(t *testing.T) {
+	// The example from Kraken's REST authentication documentation.
+	secret, err := base64.StdEncoding.DecodeString("kQH5HW/8p1uGOVjbgWA7FunAmGO8lsSUXNsu3eow76sz84Q18fWxnyRzBHCd3pd5nE9qa99HAZtuZuj6F1huXg==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	postData := []byte("nonce=1616492376594&ordertype=limit&pair=XBTUSD&price=37500&type=buy&volume=1.25")
+
+	got := signKrakenSpotRequest(secret, "/0/private/AddOrder", "1616492376594", postData)
+	want := "4/dpxb3iT4tp/ZCVEwSnEsLxx0bqyhLpdfOpc6fn7OR8+UClSV5n9E6aSS8MPtnRfp32bAb0nmbRn6H8ndwLUQ=="
+	if got != want {
+		t.Errorf("signKrakenSpotRequest() = %s, want %s", got, want)
+	}
+}
+
+// checkUniqueNonces calls each generator from its own goroutine and fails on
+// a repeated nonce.
+func checkUniqueNonces(t *testing.T, generators []NonceGenerator, calls int) {
+	t.Helper()
+	nonces := make(chan uint64, len(generators)*calls)
+	var wg sync.WaitGroup
+	for _, g := range generators {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range calls {
+				nonce, err := g.Nonce(context.Background())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				nonces <- nonce
+			}
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	seen := make(map[uint64]bool, len(generators)*calls)
+	for nonce := range nonces {
+		if seen[nonce] {
+			t.Fatalf("nonce %d returned twice", nonce)
+		}
+		seen[nonce] = true
+	}
+}
+
+func TestTimeNonceGeneratorConcurrent(t *testing.T) {
+	g := NewTimeNonceGenerator()
+	generators := make([]NonceGenerator, 16)
+	for i := range generators {
+		generators[i] = g
+	}
+	checkUniqueNonces(t, generators, 1000)
+}
+
+func TestFileNonceGeneratorShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nonce")
+	a, b := NewFileNonceGenerator(path), NewFileNonceGenerator(path)
+
+	first, err := a.Nonce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := b.Nonce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second <= first {
+		t.Errorf("second nonce %d is not greater than %d", second, first)
+	}
+
+	// Separate generators stand in for processes sharing the file.
+	checkUniqueNonces(t, []NonceGenerator{a, b, NewFileNonceGenerator(path)}, 50)
+}
+
+func TestFileNonceGeneratorWaitsForLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nonce")
+	g := NewFileNonceGenerator(path)
+	if _, err := g.Nonce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process holding the lock.
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if locked, err := tryLockFile(f); !locked || err != nil {
+		t.Fatalf("tryLockFile() = %t, %v", locked, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := g.Nonce(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Nonce() with the file locked error = %v, want context.DeadlineExceeded", err)
+	}
+
+	if err := unlockFile(f); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Nonce(context.Background()); err != nil {
+		t.Errorf("Nonce() after unlock error = %v", err)
+	}
+}
+
+func TestPrivateRequestSigned(t *testing.T) {
+	secret := "kQH5HW/8p1uGOVjbgWA7FunAmGO8lsSUXNsu3eow76sz84Q18fWxnyRzBHCd3pd5nE9qa99HAZtuZuj6F1huXg=="
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Errorf("body %q: %v", body, err)
+		}
+
+		if r.URL.Path != "/0/private/Balance" {
+			t.Errorf("path = %s, want /0/private/Balance", r.URL.Path)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %s", got)
+		}
+		if got := r.Header.Get("API-Key"); got != "key" {
+			t.Errorf("API-Key = %s, want key", got)
+		}
+		if form.Get("nonce") != "1616492376594" || form.Get("otp") != "123456" {
+			t.Errorf("form = %v, want nonce 1616492376594 and otp 123456", form)
+		}
+		decoded, _ := base64.StdEncoding.DecodeString(secret)
+		if got, want := r.Header.Get("API-Sign"), signKrakenSpotRequest(decoded, r.URL.Path, form.Get("nonce"), body); got != want {
+			t.Errorf("API-Sign = %s, want %s", got, want)
+		}
+		w.Write([]byte(`{"error":[],"result":{"ZUSD":"171288.6158","XXBT":"0.0011"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewKrakenSpotHttpClient(
+		WithBaseURL(srv.URL+"/0"),
+		WithCredentials("key", secret),
+		WithOTP(func() (string, error) { return "123456", nil }),
+		WithNonceGenerator(fixedNonceGenerator(1616492376594)),
+	)
+	balance, err := c.GetBalance()
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+	if got := balance["ZUSD"].String(); got != "171288.6158" {
+		t.Errorf("ZUSD balance = %s, want 171288.6158", got)
+	}
+}
+
+type fixedNonceGenerator uint64
+
+func (g fixedNonceGenerator) Nonce(ctx context.Context) (uint64, error) {
+	return uint64(g), nil
+}
+
+func TestPrivateRequestWithoutCredentials(t *testing.T) {
+	c := NewKrakenSpotHttpClient(WithBaseURL("http://127.0.0.1:0"))
+	if _, err := c.GetBalance(); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("GetBalance() error = %v, want ErrNoCredentials", err)
+	}
+
+	c = NewKrakenSpotHttpClient(WithBaseURL("http://127.0.0.1:0"), WithCredentials("key", "not base64!"))
+	if _, err := c.GetBalance(); err == nil {
+		t.Error("GetBalance() with an invalid secret succeeded")
+	}
+}
@@ -0,0 +1,70 @@
+package kraken
+
+// decimalPercent converts a percentage to a fraction.
+var decimalPercent = NewDecimal(1, 2)
+
+// FeePercent returns the fee in percent of the schedule tier for a 30 day
+// trading volume of volume30d, expressed in FeeVolumneCurrency. Pairs without
+// a maker schedule charge makers the taker fees.
+func (p KrakenSpotAssetPairInfo) FeePercent(volume30d Decimal, maker bool) Decimal {
+	schedule := p.Fees
+	if maker && len(p.FeesMaker) > 0 {
+		schedule = p.FeesMaker
+	}
+
+	var percent Decimal
+	for _, tier := range schedule {
+		if tier.Volume.GreaterThan(volume30d) {
+			break
+		}
+		percent = tier.PercentFee
+	}
+	return percent
+}
+
+// ExpectedFee returns the fee, in the quote currency, of trading volume at
+// price for an account with a 30 day trading volume of volume30d.
+func (p KrakenSpotAssetPairInfo) ExpectedFee(volume30d, price, volume Decimal, maker bool) Decimal {
+	return expectedFee(p.FeePercent(volume30d, maker), price, volume)
+}
+
+// FeePercent returns the fee in percent the account pays on pair, where info
+// is the pair's asset pair info. ok reports whether the fee is the one
+// GetTradeVolume reported for the pair; otherwise it is the tier of the
+// account's volume in the schedule of info, which misses any discount the
+// account has.
+//
+// The fee is looked up by pair and by each name in info, so any name of the
+// pair finds a fee keyed by another one, e.g. "XBTUSD" finds "XXBTZUSD".
+func (v KrakenSpotTradeVolume) FeePercent(pair string, info KrakenSpotAssetPairInfo, maker bool) (percent Decimal, ok bool) {
+	fees := v.Fees
+	if maker && len(v.FeesMaker) > 0 {
+		fees = v.FeesMaker
+	}
+
+	// The canonical name is not part of the info; for the pairs whose altname
+	// differs from it, it is the base asset followed by the quote asset.
+	names := make(map[string]bool, 4)
+	for _, name := range []string{pair, info.AlternateName, info.WebsocketName, info.Base + info.Quote} {
+		if len(name) > 0 {
+			names[normalizePairName(name)] = true
+		}
+	}
+	for key, fee := range fees {
+		if names[normalizePairName(key)] {
+			return fee.Fee, true
+		}
+	}
+	return info.FeePercent(v.Volume, maker), false
+}
+
+// ExpectedFee returns the fee, in the quote currency, the account pays for
+// trading volume of pair at price. See FeePercent for the fee used and ok.
+func (v KrakenSpotTradeVolume) ExpectedFee(pair string, info KrakenSpotAssetPairInfo, price, volume Decimal, maker bool) (fee Decimal, ok bool) {
+	percent, ok := v.FeePercent(pair, info, maker)
+	return expectedFee(percent, price, volume), ok
+}
+
+func expectedFee(percent, price, volume Decimal) Decimal {
+	return price.Mul(volume).Mul(percent).Mul(decimalPercent)
+}
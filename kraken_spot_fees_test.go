@@ -0,0 +1,45 @@
+package kraken
+
+import "testing"
+
+func TestKrakenSpotTradeVolumeFeePercent(t *testing.T) {
+	info := KrakenSpotAssetPairInfo{
+		AlternateName: "XBTUSD",
+		WebsocketName: "XBT/USD",
+		Base:          "XXBT",
+		Quote:         "ZUSD",
+		Fees: []KrakenSpotFeeTier{
+			{MustParseDecimal("0"), MustParseDecimal("0.26")},
+			{MustParseDecimal("50000"), MustParseDecimal("0.24")},
+		},
+		FeesMaker: []KrakenSpotFeeTier{
+			{MustParseDecimal("0"), MustParseDecimal("0.16")},
+			{MustParseDecimal("50000"), MustParseDecimal("0.14")},
+		},
+	}
+	v := KrakenSpotTradeVolume{
+		Volume:    MustParseDecimal("60000"),
+		Fees:      map[string]KrakenSpotFeeInfo{"XXBTZUSD": {Fee: MustParseDecimal("0.2")}},
+		FeesMaker: map[string]KrakenSpotFeeInfo{"XXBTZUSD": {Fee: MustParseDecimal("0.1")}},
+	}
+
+	for _, pair := range []string{"XXBTZUSD", "XBTUSD", "XBT/USD", "xbt/usd"} {
+		if got, ok := v.FeePercent(pair, info, false); !ok || got.String() != "0.2" {
+			t.Errorf("FeePercent(%s, taker) = %s, %t, want 0.2, true", pair, got, ok)
+		}
+		if got, ok := v.FeePercent(pair, info, true); !ok || got.String() != "0.1" {
+			t.Errorf("FeePercent(%s, maker) = %s, %t, want 0.1, true", pair, got, ok)
+		}
+	}
+
+	// Without a reported fee the schedule tier of the volume is used.
+	v.Fees, v.FeesMaker = nil, nil
+	if got, ok := v.FeePercent("XBTUSD", info, false); ok || got.String() != "0.24" {
+		t.Errorf("FeePercent(XBTUSD) without fees = %s, %t, want 0.24, false", got, ok)
+	}
+
+	fee, ok := v.ExpectedFee("XBTUSD", info, MustParseDecimal("30000"), MustParseDecimal("0.5"), true)
+	if ok || !fee.Equal(MustParseDecimal("21")) {
+		t.Errorf("ExpectedFee() = %s, %t, want 21, false", fee, ok)
+	}
+}
@@ -1,44 +1,47 @@
 package kraken
 
 import (
+	"context"
 	"encoding/json"
-	"io"
-	"net/http"
+	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 const DEFAULT_TRADES_CAPACITY int = 10
 
 type KrakenSpotHttpClient struct {
-	baseURL string
-}
+	baseURL     string
+	transport   *httpTransport
+	credentials *krakenSpotCredentials
 
-func NewKrakenSpotHttpClient() *KrakenSpotHttpClient {
-	return &KrakenSpotHttpClient{baseURL: "https://api.kraken.com/0"}
+	pairNamesMu    sync.Mutex
+	pairNamesCache KrakenSpotPairNames
 }
 
-func (c *KrakenSpotHttpClient) get(url string) ([]byte, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+func NewKrakenSpotHttpClient(opts ...ClientOption) *KrakenSpotHttpClient {
+	o := newClientOptions(KrakenSpotBaseURL, opts)
+	return &KrakenSpotHttpClient{
+		baseURL:     o.baseURL,
+		transport:   newHttpTransport(o, decodeKrakenSpotError),
+		credentials: newKrakenSpotCredentials(o),
 	}
-	defer resp.Body.Close()
-
-	// TODO: check response code here.
-	// I think I always want a 2XX code
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	return body, nil
+func (c *KrakenSpotHttpClient) get(ctx context.Context, url string) ([]byte, error) {
+	return c.transport.get(ctx, url)
 }
 
 func (c *KrakenSpotHttpClient) GetServerTime() (KrakenSpotServerTime, error) {
+	return c.GetServerTimeWithContext(context.Background())
+}
+
+func (c *KrakenSpotHttpClient) GetServerTimeWithContext(ctx context.Context) (KrakenSpotServerTime, error) {
 	endpoint := "/public/Time"
 	url := c.baseURL + endpoint
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return KrakenSpotServerTime{}, err
 	}
@@ -51,18 +54,20 @@ func (c *KrakenSpotHttpClient) GetServerTime() (KrakenSpotServerTime, error) {
 	}
 
 	if len(d.Error) > 0 {
-		// FIXME: I don't know if len of errors is ever greater than 1.
-		// I don't see any examples of that on the kraken website
-		return KrakenSpotServerTime{}, &KrakenError{d.Error[0]}
+		return KrakenSpotServerTime{}, newKrakenSpotError(d.Error)
 	}
 
 	return d.Result, nil
 }
 
 func (c *KrakenSpotHttpClient) GetSystemStatus() (KrakenSpotSystemStatus, error) {
+	return c.GetSystemStatusWithContext(context.Background())
+}
+
+func (c *KrakenSpotHttpClient) GetSystemStatusWithContext(ctx context.Context) (KrakenSpotSystemStatus, error) {
 	endpoint := "/public/SystemStatus"
 	url := c.baseURL + endpoint
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return KrakenSpotSystemStatus{}, err
 	}
@@ -75,20 +80,43 @@ func (c *KrakenSpotHttpClient) GetSystemStatus() (KrakenSpotSystemStatus, error)
 	}
 
 	if len(d.Error) > 0 {
-		// FIXME: I don't know if len of errors is ever greater than 1.
-		// I don't see any examples of that on the kraken website
-		return KrakenSpotSystemStatus{}, &KrakenError{d.Error[0]}
+		return KrakenSpotSystemStatus{}, newKrakenSpotError(d.Error)
 	}
 
 	return d.Result, nil
 }
 
 func (c *KrakenSpotHttpClient) GetAssetInfo() (map[string]KrakenSpotAssetInfo, error) {
-	// TODO: handle argument for getting specific assets, e.g. (assets []string)
-	// the default is to get all assets
+	return c.GetAssetInfoWithContext(context.Background())
+}
+
+func (c *KrakenSpotHttpClient) GetAssetInfoWithContext(ctx context.Context) (map[string]KrakenSpotAssetInfo, error) {
+	return c.GetAssetInfoForAssetsWithContext(ctx, nil, "")
+}
+
+// GetAssetInfoForAssets returns the info of the given assets, keyed by Kraken's
+// asset names. An empty assets or assetClass is not sent, so GetAssetInfoForAssets(nil, "")
+// returns every asset.
+func (c *KrakenSpotHttpClient) GetAssetInfoForAssets(assets []string, assetClass KrakenAssetClass) (map[string]KrakenSpotAssetInfo, error) {
+	return c.GetAssetInfoForAssetsWithContext(context.Background(), assets, assetClass)
+}
+
+func (c *KrakenSpotHttpClient) GetAssetInfoForAssetsWithContext(ctx context.Context, assets []string, assetClass KrakenAssetClass) (map[string]KrakenSpotAssetInfo, error) {
+	// query params
+	params := url.Values{}
+	if len(assets) > 0 {
+		params.Add("asset", strings.Join(assets, ","))
+	}
+	if len(assetClass) > 0 {
+		params.Add("aclass", string(assetClass))
+	}
+
 	endpoint := "/public/Assets"
 	url := c.baseURL + endpoint
-	body, err := c.get(url)
+	if len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -101,25 +129,46 @@ func (c *KrakenSpotHttpClient) GetAssetInfo() (map[string]KrakenSpotAssetInfo, e
 	}
 
 	if len(d.Error) > 0 {
-		// FIXME: I don't know if len of errors is ever greater than 1.
-		// I don't see any examples of that on the kraken website
-		return nil, &KrakenError{d.Error[0]}
+		return nil, newKrakenSpotError(d.Error)
 	}
 
 	return d.Result, nil
 }
 
 func (c *KrakenSpotHttpClient) GetAssetPairs() (map[string]KrakenSpotAssetPairInfo, error) {
-	// TODO: handle argument for getting specific asset pairs, e.g. (pairs []string)
-	// the default is to get all assets
-	// params := url.Values{}
-	// params.Add("pair", pair)
+	return c.GetAssetPairsWithContext(context.Background())
+}
 
-	// queryString := params.Encode()
+func (c *KrakenSpotHttpClient) GetAssetPairsWithContext(ctx context.Context) (map[string]KrakenSpotAssetPairInfo, error) {
+	return c.GetAssetPairsForPairsWithContext(ctx, nil, "", "")
+}
+
+// GetAssetPairsForPairs returns the info of the given pairs, keyed by Kraken's
+// canonical pair names. info selects the returned fields and defaults to all of
+// them. An empty pairs, info or assetClass is not sent.
+func (c *KrakenSpotHttpClient) GetAssetPairsForPairs(pairs []string, info KrakenSpotAssetPairsInfo, assetClass KrakenAssetClass) (map[string]KrakenSpotAssetPairInfo, error) {
+	return c.GetAssetPairsForPairsWithContext(context.Background(), pairs, info, assetClass)
+}
+
+func (c *KrakenSpotHttpClient) GetAssetPairsForPairsWithContext(ctx context.Context, pairs []string, info KrakenSpotAssetPairsInfo, assetClass KrakenAssetClass) (map[string]KrakenSpotAssetPairInfo, error) {
+	// query params
+	params := url.Values{}
+	if len(pairs) > 0 {
+		params.Add("pair", strings.Join(pairs, ","))
+	}
+	if len(info) > 0 {
+		params.Add("info", string(info))
+	}
+	if len(assetClass) > 0 {
+		params.Add("aclass_base", string(assetClass))
+	}
 
 	endpoint := "/public/AssetPairs"
 	url := c.baseURL + endpoint
-	body, err := c.get(url)
+	if len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -132,15 +181,17 @@ func (c *KrakenSpotHttpClient) GetAssetPairs() (map[string]KrakenSpotAssetPairIn
 	}
 
 	if len(d.Error) > 0 {
-		// FIXME: I don't know if len of errors is ever greater than 1.
-		// I don't see any examples of that on the kraken website
-		return nil, &KrakenError{d.Error[0]}
+		return nil, newKrakenSpotError(d.Error)
 	}
 
 	return d.Result, nil
 }
 
 func (c *KrakenSpotHttpClient) GetTickerInfo(pair string) (KrakenSpotAssetTickerInfo, error) {
+	return c.GetTickerInfoWithContext(context.Background(), pair)
+}
+
+func (c *KrakenSpotHttpClient) GetTickerInfoWithContext(ctx context.Context, pair string) (KrakenSpotAssetTickerInfo, error) {
 	// query params
 	params := url.Values{}
 	params.Add("pair", pair)
@@ -148,7 +199,7 @@ func (c *KrakenSpotHttpClient) GetTickerInfo(pair string) (KrakenSpotAssetTicker
 
 	endpoint := "/public/Ticker"
 	url := c.baseURL + endpoint + "?" + queryString
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return KrakenSpotAssetTickerInfo{}, err
 	}
@@ -161,15 +212,58 @@ func (c *KrakenSpotHttpClient) GetTickerInfo(pair string) (KrakenSpotAssetTicker
 	}
 
 	if len(d.Error) > 0 {
-		// FIXME: I don't know if len of errors is ever greater than 1.
-		// I don't see any examples of that on the kraken website
-		return KrakenSpotAssetTickerInfo{}, &KrakenError{d.Error[0]}
+		return KrakenSpotAssetTickerInfo{}, newKrakenSpotError(d.Error)
+	}
+
+	return lookupPairResult(ctx, c, d.Result, pair)
+}
+
+// GetTickerInfoForPairs returns the tickers of the given pairs, keyed by
+// Kraken's canonical pair names. An empty pairs returns every ticker and an
+// empty assetClass is not sent.
+func (c *KrakenSpotHttpClient) GetTickerInfoForPairs(pairs []string, assetClass KrakenAssetClass) (map[string]KrakenSpotAssetTickerInfo, error) {
+	return c.GetTickerInfoForPairsWithContext(context.Background(), pairs, assetClass)
+}
+
+func (c *KrakenSpotHttpClient) GetTickerInfoForPairsWithContext(ctx context.Context, pairs []string, assetClass KrakenAssetClass) (map[string]KrakenSpotAssetTickerInfo, error) {
+	// query params
+	params := url.Values{}
+	if len(pairs) > 0 {
+		params.Add("pair", strings.Join(pairs, ","))
+	}
+	if len(assetClass) > 0 {
+		params.Add("asset_class", string(assetClass))
+	}
+
+	endpoint := "/public/Ticker"
+	url := c.baseURL + endpoint
+	if len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenTickerResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.Error) > 0 {
+		return nil, newKrakenSpotError(d.Error)
 	}
 
-	return d.Result[pair], nil
+	return d.Result, nil
 }
 
 func (c *KrakenSpotHttpClient) GetOrderBook(pair string, depth int) (KrakenSpotOrderBook, error) {
+	return c.GetOrderBookWithContext(context.Background(), pair, depth)
+}
+
+func (c *KrakenSpotHttpClient) GetOrderBookWithContext(ctx context.Context, pair string, depth int) (KrakenSpotOrderBook, error) {
 	// query params
 	params := url.Values{}
 	params.Add("pair", pair)
@@ -178,7 +272,7 @@ func (c *KrakenSpotHttpClient) GetOrderBook(pair string, depth int) (KrakenSpotO
 
 	endpoint := "/public/Depth"
 	url := c.baseURL + endpoint + "?" + queryString
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
 		return KrakenSpotOrderBook{}, err
 	}
@@ -191,23 +285,25 @@ func (c *KrakenSpotHttpClient) GetOrderBook(pair string, depth int) (KrakenSpotO
 	}
 
 	if len(d.Error) > 0 {
-		// FIXME: I don't know if len of errors is ever greater than 1.
-		// I don't see any examples of that on the kraken website
-		return KrakenSpotOrderBook{}, &KrakenError{d.Error[0]}
+		return KrakenSpotOrderBook{}, newKrakenSpotError(d.Error)
 	}
 
-	return d.Result[pair], nil
+	return lookupPairResult(ctx, c, d.Result, pair)
+}
+
+func (c *KrakenSpotHttpClient) GetTrades(pair string, since KrakenCursor, count int) (KrakenSpotTradeInfo, error) {
+	return c.GetTradesWithContext(context.Background(), pair, since, count)
 }
 
-func (c *KrakenSpotHttpClient) GetTrades(pair string, since string, count int) (KrakenSpotTradeInfo, error) {
+func (c *KrakenSpotHttpClient) GetTradesWithContext(ctx context.Context, pair string, since KrakenCursor, count int) (KrakenSpotTradeInfo, error) {
 
 	// query params
 	params := url.Values{}
 	params.Add("pair", pair)
 
-	// empty string means the query param for "since" is ignored
-	if len(since) > 0 {
-		params.Add("since", since)
+	// the zero cursor means the query param for "since" is not sent
+	if !since.IsZero() {
+		params.Add("since", since.String())
 	}
 
 	// count of 0 means the query param for "count" is ignored
@@ -219,54 +315,145 @@ func (c *KrakenSpotHttpClient) GetTrades(pair string, since string, count int) (
 	endpoint := "/public/Trades"
 	url := c.baseURL + endpoint + "?" + queryString
 
-	body, err := c.get(url)
+	body, err := c.get(ctx, url)
 	if err != nil {
-		return KrakenSpotTradeInfo{nil, ""}, err
+		return KrakenSpotTradeInfo{}, err
 	}
 
 	// Unmarshall the response body into a struct
 	var d KrakenSpotTradeResponse
 	err = json.Unmarshal(body, &d)
 	if err != nil {
-		return KrakenSpotTradeInfo{nil, ""}, err
+		return KrakenSpotTradeInfo{}, err
 	}
 
 	if len(d.Error) > 0 {
-		// FIXME: I don't know if len of errors is ever greater than 1.
-		// I don't see any examples of that on the kraken website
-		return KrakenSpotTradeInfo{nil, ""}, &KrakenError{d.Error[0]}
+		return KrakenSpotTradeInfo{}, newKrakenSpotError(d.Error)
+	}
+
+	entries, err := lookupPairResult(ctx, c, d.Result, pair)
+	if err != nil {
+		return KrakenSpotTradeInfo{}, err
 	}
 
 	out := make([]KrakenSpotTradeEntry, 0, DEFAULT_TRADES_CAPACITY)
+	err = json.Unmarshal(entries, &out)
+	if err != nil {
+		return KrakenSpotTradeInfo{}, err
+	}
 
-	result := d.Result
-	last := result["last"].(string)
-
-	trades := result[pair].([]any)
-	for _, trades_ := range trades {
-
-		// [<price>, <volume>, <time>, <buy/sell>, <market/limit>, <miscellaneous>, <trade_id>]
-		trade_info := trades_.([]any)
-
-		trade_price, err := strconv.ParseFloat(trade_info[0].(string), 64)
-		if err != nil {
-			return KrakenSpotTradeInfo{nil, ""}, err
-		}
-		trade_size, err := strconv.ParseFloat(trade_info[1].(string), 64)
-		if err != nil {
-			return KrakenSpotTradeInfo{nil, ""}, err
-		}
-		trade := KrakenSpotTradeEntry{
-			trade_price,
-			trade_size,
-			trade_info[2].(float64),
-			trade_info[3].(string),
-			trade_info[4].(string),
-			trade_info[5].(string),
-			trade_info[6].(float64),
-		}
-		out = append(out, trade)
+	var last KrakenCursor
+	err = json.Unmarshal(d.Result["last"], &last)
+	if err != nil {
+		return KrakenSpotTradeInfo{}, fmt.Errorf("kraken: decoding last: %w", err)
 	}
 
 	return KrakenSpotTradeInfo{out, last}, nil
 }
+
+func (c *KrakenSpotHttpClient) GetOHLC(pair string, interval KrakenSpotInterval, since KrakenCursor) (KrakenSpotOHLCInfo, error) {
+	return c.GetOHLCWithContext(context.Background(), pair, interval, since)
+}
+
+func (c *KrakenSpotHttpClient) GetOHLCWithContext(ctx context.Context, pair string, interval KrakenSpotInterval, since KrakenCursor) (KrakenSpotOHLCInfo, error) {
+	if !interval.Valid() {
+		return KrakenSpotOHLCInfo{}, fmt.Errorf("kraken: unsupported OHLC interval %d", interval)
+	}
+
+	// query params
+	params := url.Values{}
+	params.Add("pair", pair)
+	params.Add("interval", strconv.Itoa(int(interval)))
+
+	// the zero cursor means the query param for "since" is not sent
+	if !since.IsZero() {
+		params.Add("since", since.String())
+	}
+	queryString := params.Encode()
+
+	endpoint := "/public/OHLC"
+	url := c.baseURL + endpoint + "?" + queryString
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return KrakenSpotOHLCInfo{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotOHLCResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotOHLCInfo{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotOHLCInfo{}, newKrakenSpotError(d.Error)
+	}
+
+	entries, err := lookupPairResult(ctx, c, d.Result, pair)
+	if err != nil {
+		return KrakenSpotOHLCInfo{}, err
+	}
+
+	var out KrakenSpotOHLCInfo
+	err = json.Unmarshal(entries, &out.Candles)
+	if err != nil {
+		return KrakenSpotOHLCInfo{}, err
+	}
+	err = json.Unmarshal(d.Result["last"], &out.Last)
+	if err != nil {
+		return KrakenSpotOHLCInfo{}, fmt.Errorf("kraken: decoding last: %w", err)
+	}
+
+	return out, nil
+}
+
+func (c *KrakenSpotHttpClient) GetRecentSpreads(pair string, since KrakenCursor) (KrakenSpotSpreadInfo, error) {
+	return c.GetRecentSpreadsWithContext(context.Background(), pair, since)
+}
+
+func (c *KrakenSpotHttpClient) GetRecentSpreadsWithContext(ctx context.Context, pair string, since KrakenCursor) (KrakenSpotSpreadInfo, error) {
+	// query params
+	params := url.Values{}
+	params.Add("pair", pair)
+
+	// the zero cursor means the query param for "since" is not sent
+	if !since.IsZero() {
+		params.Add("since", since.String())
+	}
+	queryString := params.Encode()
+
+	endpoint := "/public/Spread"
+	url := c.baseURL + endpoint + "?" + queryString
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return KrakenSpotSpreadInfo{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotSpreadResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotSpreadInfo{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotSpreadInfo{}, newKrakenSpotError(d.Error)
+	}
+
+	entries, err := lookupPairResult(ctx, c, d.Result, pair)
+	if err != nil {
+		return KrakenSpotSpreadInfo{}, err
+	}
+
+	var out KrakenSpotSpreadInfo
+	err = json.Unmarshal(entries, &out.Spreads)
+	if err != nil {
+		return KrakenSpotSpreadInfo{}, err
+	}
+	err = json.Unmarshal(d.Result["last"], &out.Last)
+	if err != nil {
+		return KrakenSpotSpreadInfo{}, fmt.Errorf("kraken: decoding last: %w", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,32 @@
+package kraken
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRecentSpreadsSince(t *testing.T) {
+	var queries []map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":[[1688671834,"30292.10000","30297.50000"]],"last":1688672106}}`))
+	}))
+	defer srv.Close()
+
+	c := NewKrakenSpotHttpClient(WithBaseURL(srv.URL))
+	first, err := c.GetRecentSpreads("XXBTZUSD", KrakenCursor{})
+	if err != nil {
+		t.Fatalf("GetRecentSpreads() error = %v", err)
+	}
+	if _, err := c.GetRecentSpreads("XXBTZUSD", first.Last); err != nil {
+		t.Fatalf("GetRecentSpreads(last) error = %v", err)
+	}
+
+	if _, ok := queries[0]["since"]; ok {
+		t.Errorf("zero cursor sent since = %v", queries[0]["since"])
+	}
+	if got := queries[1]["since"]; len(got) != 1 || got[0] != "1688672106" {
+		t.Errorf("since = %v, want [1688672106]", got)
+	}
+}
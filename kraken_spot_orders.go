@@ -0,0 +1,706 @@
+package kraken
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KrakenSpotPriceOffset is the prefix of a price relative to the last traded
+// price.
+type KrakenSpotPriceOffset string
+
+const (
+	// KrakenSpotPriceAbsolute is an absolute price.
+	KrakenSpotPriceAbsolute KrakenSpotPriceOffset = ""
+	KrakenSpotPricePlus     KrakenSpotPriceOffset = "+"
+	KrakenSpotPriceMinus    KrakenSpotPriceOffset = "-"
+	// KrakenSpotPriceAuto lets Kraken add or subtract the offset depending on
+	// the side of the order.
+	KrakenSpotPriceAuto KrakenSpotPriceOffset = "#"
+)
+
+// KrakenSpotOrderPrice is a price or trigger price of a spot order: an
+// absolute price ("27500.0"), an offset from the last traded price ("+150",
+// "#50") or an offset in percent ("-2.5%"). The zero value is no price.
+type KrakenSpotOrderPrice struct {
+	Value  Decimal
+	Offset KrakenSpotPriceOffset
+	// Percent expresses the offset in percent of the last traded price.
+	Percent bool
+}
+
+// NewKrakenSpotPrice returns the absolute price value.
+func NewKrakenSpotPrice(value Decimal) KrakenSpotOrderPrice {
+	return KrakenSpotOrderPrice{Value: value}
+}
+
+// NewKrakenSpotPriceOffset returns value away from the last traded price in
+// the direction of offset.
+func NewKrakenSpotPriceOffset(offset KrakenSpotPriceOffset, value Decimal) KrakenSpotOrderPrice {
+	return KrakenSpotOrderPrice{Value: value, Offset: offset}
+}
+
+// NewKrakenSpotPricePercent returns percent percent away from the last traded
+// price in the direction of offset.
+func NewKrakenSpotPricePercent(offset KrakenSpotPriceOffset, percent Decimal) KrakenSpotOrderPrice {
+	return KrakenSpotOrderPrice{Value: percent, Offset: offset, Percent: true}
+}
+
+func (p KrakenSpotOrderPrice) IsZero() bool {
+	return p.Value.IsZero() && len(p.Offset) == 0
+}
+
+// String returns the price in the form Kraken expects, or "" for no price.
+func (p KrakenSpotOrderPrice) String() string {
+	if p.IsZero() {
+		return ""
+	}
+	s := string(p.Offset) + p.Value.String()
+	if p.Percent {
+		s += "%"
+	}
+	return s
+}
+
+// ParseKrakenSpotOrderPrice parses a price in any of the forms String
+// returns. "" and "0" parse to no price.
+func ParseKrakenSpotOrderPrice(s string) (KrakenSpotOrderPrice, error) {
+	var p KrakenSpotOrderPrice
+	text := s
+	if len(text) > 0 {
+		switch offset := KrakenSpotPriceOffset(text[:1]); offset {
+		case KrakenSpotPricePlus, KrakenSpotPriceMinus, KrakenSpotPriceAuto:
+			p.Offset = offset
+			text = text[1:]
+		}
+	}
+	if rest, ok := strings.CutSuffix(text, "%"); ok {
+		p.Percent = true
+		text = rest
+	}
+	if len(text) == 0 && !p.Percent && len(p.Offset) == 0 {
+		return KrakenSpotOrderPrice{}, nil
+	}
+	if strings.HasPrefix(text, "+") || strings.HasPrefix(text, "-") {
+		return KrakenSpotOrderPrice{}, fmt.Errorf("kraken: invalid order price %q", s)
+	}
+
+	value, err := ParseDecimal(text)
+	if err != nil {
+		return KrakenSpotOrderPrice{}, fmt.Errorf("kraken: invalid order price %q", s)
+	}
+	p.Value = value
+	return p, nil
+}
+
+// MarshalJSON encodes the price as the string String returns.
+func (p KrakenSpotOrderPrice) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON accepts a JSON number, a string in any of the forms String
+// returns, an empty string or null. The last two decode to no price.
+func (p *KrakenSpotOrderPrice) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*p = KrakenSpotOrderPrice{}
+		return nil
+	}
+
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseKrakenSpotOrderPrice(text)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// KrakenSpotCloseOrder is the conditional close order placed when the order
+// it is attached to fills.
+type KrakenSpotCloseOrder struct {
+	OrderType KrakenOrderType
+	Price     KrakenSpotOrderPrice
+	Price2    KrakenSpotOrderPrice
+}
+
+// KrakenSpotOrderRequest is an order to place with AddOrder. Zero values of
+// the optional fields are not sent, so Kraken applies its defaults.
+type KrakenSpotOrderRequest struct {
+	Pair      string
+	Side      KrakenSide
+	OrderType KrakenOrderType
+	// Volume is in the base currency, or in the quote currency for market buys
+	// with KrakenSpotOrderFlagVolumeInQuote. It is always sent: 0 closes the
+	// whole margin position of a settle-position order.
+	Volume Decimal
+	// DisplayVolume is the visible volume of an iceberg order.
+	DisplayVolume Decimal
+	// Price is the limit price, or the trigger price of stop-loss, take-profit
+	// and trailing-stop orders.
+	Price KrakenSpotOrderPrice
+	// Price2 is the limit price of stop-loss-limit, take-profit-limit and
+	// trailing-stop-limit orders.
+	Price2  KrakenSpotOrderPrice
+	Trigger KrakenSpotOrderTrigger
+	// Leverage of a margin order, 0 for a spot order.
+	Leverage            int
+	ReduceOnly          bool
+	SelfTradePrevention KrakenSpotSelfTradePrevention
+	Flags               []KrakenSpotOrderFlag
+	TimeInForce         KrakenSpotTimeInForce
+	// StartTime and ExpireTime are "0" for now, "+<n>" for n seconds from now
+	// or a Unix timestamp. ExpireTime is required by GTD orders.
+	StartTime  string
+	ExpireTime string
+	// UserRef and ClientOrderID identify the order in later requests; at most
+	// one of them may be set.
+	UserRef       int32
+	ClientOrderID string
+	Close         *KrakenSpotCloseOrder
+	// Deadline rejects the order if Kraken's matching engine receives it later.
+	Deadline time.Time
+	// Validate only checks the order: Kraken does not place it and returns no
+	// transaction ID.
+	Validate bool
+}
+
+func (o KrakenSpotOrderRequest) validate() error {
+	if len(o.Pair) == 0 {
+		return fmt.Errorf("%w: order pair is required", ErrInvalidArguments)
+	}
+	if !o.Side.Valid() {
+		return fmt.Errorf("%w: invalid order side %q", ErrInvalidArguments, o.Side)
+	}
+	if !o.OrderType.Valid() {
+		return fmt.Errorf("%w: invalid order type %q", ErrInvalidArguments, o.OrderType)
+	}
+	if o.UserRef != 0 && len(o.ClientOrderID) > 0 {
+		return fmt.Errorf("%w: userref and cl_ord_id are mutually exclusive", ErrInvalidArguments)
+	}
+	if o.Close != nil && !o.Close.OrderType.Valid() {
+		return fmt.Errorf("%w: invalid close order type %q", ErrInvalidArguments, o.Close.OrderType)
+	}
+	return nil
+}
+
+// params encodes the order as AddOrder form parameters.
+func (o KrakenSpotOrderRequest) params() (url.Values, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("pair", o.Pair)
+	params.Add("type", string(o.Side))
+	params.Add("ordertype", string(o.OrderType))
+	params.Add("volume", o.Volume.String())
+	if !o.DisplayVolume.IsZero() {
+		params.Add("displayvol", o.DisplayVolume.String())
+	}
+	if !o.Price.IsZero() {
+		params.Add("price", o.Price.String())
+	}
+	if !o.Price2.IsZero() {
+		params.Add("price2", o.Price2.String())
+	}
+	if len(o.Trigger) > 0 {
+		params.Add("trigger", string(o.Trigger))
+	}
+	if o.Leverage > 0 {
+		params.Add("leverage", strconv.Itoa(o.Leverage))
+	}
+	if o.ReduceOnly {
+		params.Add("reduce_only", "true")
+	}
+	if len(o.SelfTradePrevention) > 0 {
+		params.Add("stptype", string(o.SelfTradePrevention))
+	}
+	if len(o.Flags) > 0 {
+		params.Add("oflags", joinOrderFlags(o.Flags))
+	}
+	if len(o.TimeInForce) > 0 {
+		params.Add("timeinforce", string(o.TimeInForce))
+	}
+	if len(o.StartTime) > 0 {
+		params.Add("starttm", o.StartTime)
+	}
+	if len(o.ExpireTime) > 0 {
+		params.Add("expiretm", o.ExpireTime)
+	}
+	if o.UserRef != 0 {
+		params.Add("userref", strconv.FormatInt(int64(o.UserRef), 10))
+	}
+	if len(o.ClientOrderID) > 0 {
+		params.Add("cl_ord_id", o.ClientOrderID)
+	}
+	if o.Close != nil {
+		params.Add("close[ordertype]", string(o.Close.OrderType))
+		if !o.Close.Price.IsZero() {
+			params.Add("close[price]", o.Close.Price.String())
+		}
+		if !o.Close.Price2.IsZero() {
+			params.Add("close[price2]", o.Close.Price2.String())
+		}
+	}
+	if !o.Deadline.IsZero() {
+		params.Add("deadline", o.Deadline.UTC().Format(time.RFC3339Nano))
+	}
+	if o.Validate {
+		params.Add("validate", "true")
+	}
+	return params, nil
+}
+
+func joinOrderFlags(flags []KrakenSpotOrderFlag) string {
+	names := make([]string, len(flags))
+	for i, flag := range flags {
+		names[i] = string(flag)
+	}
+	return strings.Join(names, ",")
+}
+
+// RoundOrder returns order with its prices rounded to the pair's price
+// decimals and its volumes truncated to the pair's lot decimals, and checks
+// the result against the pair's minimum order volume and cost. Offsets in
+// percent are left as they are, and the minimum cost is only checked for
+// absolute prices. A volume in the quote currency, set with
+// KrakenSpotOrderFlagVolumeInQuote, is a cost: it is truncated to the pair's
+// cost decimals and only checked against the minimum cost.
+func (p KrakenSpotAssetPairInfo) RoundOrder(order KrakenSpotOrderRequest) (KrakenSpotOrderRequest, error) {
+	isQuoteVolume := false
+	for _, flag := range order.Flags {
+		isQuoteVolume = isQuoteVolume || flag == KrakenSpotOrderFlagVolumeInQuote
+	}
+
+	if isQuoteVolume {
+		order.Volume = order.Volume.Truncate(int32(p.CostDecimals))
+	} else {
+		order.Volume = p.RoundVolume(order.Volume)
+	}
+	order.DisplayVolume = p.RoundVolume(order.DisplayVolume)
+	order.Price = p.roundOrderPrice(order.Price)
+	order.Price2 = p.roundOrderPrice(order.Price2)
+	if order.Close != nil {
+		closeOrder := *order.Close
+		closeOrder.Price = p.roundOrderPrice(closeOrder.Price)
+		closeOrder.Price2 = p.roundOrderPrice(closeOrder.Price2)
+		order.Close = &closeOrder
+	}
+
+	if order.Volume.IsZero() {
+		// settle-position orders close the whole position with a volume of 0
+		return order, nil
+	}
+	if isQuoteVolume {
+		if !p.CostMin.IsZero() && order.Volume.LessThan(p.CostMin) {
+			return order, fmt.Errorf("%w: cost %s below the minimum of %s", ErrInvalidArguments, order.Volume, p.CostMin)
+		}
+		return order, nil
+	}
+	if !p.OrderMin.IsZero() && order.Volume.LessThan(p.OrderMin) {
+		return order, fmt.Errorf("%w: volume %s below the minimum of %s", ErrInvalidArguments, order.Volume, p.OrderMin)
+	}
+
+	price := order.Price
+	if order.OrderType == KrakenOrderTypeStopLossLimit || order.OrderType == KrakenOrderTypeTakeProfitLimit {
+		price = order.Price2
+	}
+	if !p.CostMin.IsZero() && !price.IsZero() && price.Offset == KrakenSpotPriceAbsolute {
+		if cost := order.Volume.Mul(price.Value); cost.LessThan(p.CostMin) {
+			return order, fmt.Errorf("%w: cost %s below the minimum of %s", ErrInvalidArguments, cost, p.CostMin)
+		}
+	}
+	return order, nil
+}
+
+func (p KrakenSpotAssetPairInfo) roundOrderPrice(price KrakenSpotOrderPrice) KrakenSpotOrderPrice {
+	if !price.Percent {
+		price.Value = p.RoundPrice(price.Value)
+	}
+	return price
+}
+
+// KrakenSpotAmendOrderRequest changes an open order in place, keeping its
+// priority in the order book where Kraken allows it. The order is identified
+// by exactly one of TransactionID and ClientOrderID. Zero values of the other
+// fields are left unchanged.
+type KrakenSpotAmendOrderRequest struct {
+	TransactionID   string
+	ClientOrderID   string
+	OrderQuantity   Decimal
+	DisplayQuantity Decimal
+	LimitPrice      KrakenSpotOrderPrice
+	TriggerPrice    KrakenSpotOrderPrice
+	// PostOnly rejects the amend if the new limit price would take liquidity.
+	PostOnly bool
+	Deadline time.Time
+}
+
+func (o KrakenSpotAmendOrderRequest) params() (url.Values, error) {
+	if (len(o.TransactionID) == 0) == (len(o.ClientOrderID) == 0) {
+		return nil, fmt.Errorf("%w: exactly one of txid and cl_ord_id is required", ErrInvalidArguments)
+	}
+
+	params := url.Values{}
+	if len(o.TransactionID) > 0 {
+		params.Add("txid", o.TransactionID)
+	}
+	if len(o.ClientOrderID) > 0 {
+		params.Add("cl_ord_id", o.ClientOrderID)
+	}
+	if !o.OrderQuantity.IsZero() {
+		params.Add("order_qty", o.OrderQuantity.String())
+	}
+	if !o.DisplayQuantity.IsZero() {
+		params.Add("display_qty", o.DisplayQuantity.String())
+	}
+	if !o.LimitPrice.IsZero() {
+		params.Add("limit_price", o.LimitPrice.String())
+	}
+	if !o.TriggerPrice.IsZero() {
+		params.Add("trigger_price", o.TriggerPrice.String())
+	}
+	if o.PostOnly {
+		params.Add("post_only", "true")
+	}
+	if !o.Deadline.IsZero() {
+		params.Add("deadline", o.Deadline.UTC().Format(time.RFC3339Nano))
+	}
+	return params, nil
+}
+
+// KrakenSpotEditOrderRequest replaces an open order with a new one that takes
+// the place of the original at the back of the queue. Zero values of the
+// optional fields keep the values of the original order.
+type KrakenSpotEditOrderRequest struct {
+	// TransactionID is the transaction ID or the user reference of the order.
+	TransactionID string
+	Pair          string
+	// UserRef is the user reference of the new order.
+	UserRef       int32
+	Volume        Decimal
+	DisplayVolume Decimal
+	Price         KrakenSpotOrderPrice
+	Price2        KrakenSpotOrderPrice
+	Flags         []KrakenSpotOrderFlag
+	Deadline      time.Time
+	// CancelResponse cancels the original order even if the new order cannot
+	// be placed.
+	CancelResponse bool
+	Validate       bool
+}
+
+func (o KrakenSpotEditOrderRequest) params() (url.Values, error) {
+	if len(o.TransactionID) == 0 {
+		return nil, fmt.Errorf("%w: order txid is required", ErrInvalidArguments)
+	}
+	if len(o.Pair) == 0 {
+		return nil, fmt.Errorf("%w: order pair is required", ErrInvalidArguments)
+	}
+
+	params := url.Values{}
+	params.Add("txid", o.TransactionID)
+	params.Add("pair", o.Pair)
+	if o.UserRef != 0 {
+		params.Add("userref", strconv.FormatInt(int64(o.UserRef), 10))
+	}
+	if !o.Volume.IsZero() {
+		params.Add("volume", o.Volume.String())
+	}
+	if !o.DisplayVolume.IsZero() {
+		params.Add("displayvol", o.DisplayVolume.String())
+	}
+	if !o.Price.IsZero() {
+		params.Add("price", o.Price.String())
+	}
+	if !o.Price2.IsZero() {
+		params.Add("price2", o.Price2.String())
+	}
+	if len(o.Flags) > 0 {
+		params.Add("oflags", joinOrderFlags(o.Flags))
+	}
+	if !o.Deadline.IsZero() {
+		params.Add("deadline", o.Deadline.UTC().Format(time.RFC3339Nano))
+	}
+	if o.CancelResponse {
+		params.Add("cancel_response", "true")
+	}
+	if o.Validate {
+		params.Add("validate", "true")
+	}
+	return params, nil
+}
+
+// Kraken accepts between 2 and 15 orders in one AddOrderBatch and up to 50
+// orders in one CancelOrderBatch.
+const (
+	krakenSpotMinBatchOrders       = 2
+	krakenSpotMaxBatchOrders       = 15
+	krakenSpotMaxBatchCancelOrders = 50
+)
+
+// KrakenSpotOrderBatchRequest is a batch of orders on a single pair placed
+// with AddOrderBatch. Deadline and Validate apply to the whole batch and must
+// not be set on the orders.
+type KrakenSpotOrderBatchRequest struct {
+	Orders   []KrakenSpotOrderRequest
+	Deadline time.Time
+	Validate bool
+}
+
+// krakenSpotBatchOrder is an order in the JSON body of AddOrderBatch.
+type krakenSpotBatchOrder struct {
+	OrderType           string                     `json:"ordertype"`
+	Side                string                     `json:"type"`
+	Volume              string                     `json:"volume"`
+	DisplayVolume       string                     `json:"displayvol,omitempty"`
+	Price               string                     `json:"price,omitempty"`
+	Price2              string                     `json:"price2,omitempty"`
+	Trigger             string                     `json:"trigger,omitempty"`
+	Leverage            string                     `json:"leverage,omitempty"`
+	ReduceOnly          bool                       `json:"reduce_only,omitempty"`
+	SelfTradePrevention string                     `json:"stptype,omitempty"`
+	Flags               string                     `json:"oflags,omitempty"`
+	TimeInForce         string                     `json:"timeinforce,omitempty"`
+	StartTime           string                     `json:"starttm,omitempty"`
+	ExpireTime          string                     `json:"expiretm,omitempty"`
+	UserRef             int32                      `json:"userref,omitempty"`
+	ClientOrderID       string                     `json:"cl_ord_id,omitempty"`
+	Close               *krakenSpotBatchCloseOrder `json:"close,omitempty"`
+}
+
+type krakenSpotBatchCloseOrder struct {
+	OrderType string `json:"ordertype"`
+	Price     string `json:"price,omitempty"`
+	Price2    string `json:"price2,omitempty"`
+}
+
+func (o KrakenSpotOrderRequest) batchOrder() krakenSpotBatchOrder {
+	order := krakenSpotBatchOrder{
+		OrderType:           string(o.OrderType),
+		Side:                string(o.Side),
+		Volume:              o.Volume.String(),
+		Price:               o.Price.String(),
+		Price2:              o.Price2.String(),
+		Trigger:             string(o.Trigger),
+		ReduceOnly:          o.ReduceOnly,
+		SelfTradePrevention: string(o.SelfTradePrevention),
+		TimeInForce:         string(o.TimeInForce),
+		StartTime:           o.StartTime,
+		ExpireTime:          o.ExpireTime,
+		UserRef:             o.UserRef,
+		ClientOrderID:       o.ClientOrderID,
+	}
+	if !o.DisplayVolume.IsZero() {
+		order.DisplayVolume = o.DisplayVolume.String()
+	}
+	if o.Leverage > 0 {
+		order.Leverage = strconv.Itoa(o.Leverage)
+	}
+	if len(o.Flags) > 0 {
+		order.Flags = joinOrderFlags(o.Flags)
+	}
+	if o.Close != nil {
+		order.Close = &krakenSpotBatchCloseOrder{
+			OrderType: string(o.Close.OrderType),
+			Price:     o.Close.Price.String(),
+			Price2:    o.Close.Price2.String(),
+		}
+	}
+	return order
+}
+
+// payload encodes the batch as the JSON body of AddOrderBatch, checking that
+// its size is within Kraken's limits and that all orders share one pair.
+func (b KrakenSpotOrderBatchRequest) payload() (map[string]any, error) {
+	if len(b.Orders) < krakenSpotMinBatchOrders || len(b.Orders) > krakenSpotMaxBatchOrders {
+		return nil, fmt.Errorf("%w: a batch takes %d to %d orders, got %d",
+			ErrInvalidArguments, krakenSpotMinBatchOrders, krakenSpotMaxBatchOrders, len(b.Orders))
+	}
+
+	pair := b.Orders[0].Pair
+	orders := make([]krakenSpotBatchOrder, len(b.Orders))
+	for i, order := range b.Orders {
+		if err := order.validate(); err != nil {
+			return nil, fmt.Errorf("order %d: %w", i, err)
+		}
+		if normalizePairName(order.Pair) != normalizePairName(pair) {
+			return nil, fmt.Errorf("%w: order %d: pair %s differs from the batch pair %s", ErrInvalidArguments, i, order.Pair, pair)
+		}
+		if !order.Deadline.IsZero() || order.Validate {
+			return nil, fmt.Errorf("%w: order %d: deadline and validate must be set on the batch", ErrInvalidArguments, i)
+		}
+		orders[i] = order.batchOrder()
+	}
+
+	payload := map[string]any{
+		"pair":   pair,
+		"orders": orders,
+	}
+	if !b.Deadline.IsZero() {
+		payload["deadline"] = b.Deadline.UTC().Format(time.RFC3339Nano)
+	}
+	if b.Validate {
+		payload["validate"] = true
+	}
+	return payload, nil
+}
+
+// KrakenSpotOpenOrdersRequest filters the orders returned by GetOpenOrders.
+// Zero values are not sent.
+type KrakenSpotOpenOrdersRequest struct {
+	// Trades includes the IDs of each order's trades.
+	Trades        bool
+	UserRef       int32
+	ClientOrderID string
+}
+
+func (r KrakenSpotOpenOrdersRequest) params() url.Values {
+	params := url.Values{}
+	if r.Trades {
+		params.Add("trades", "true")
+	}
+	if r.UserRef != 0 {
+		params.Add("userref", strconv.FormatInt(int64(r.UserRef), 10))
+	}
+	if len(r.ClientOrderID) > 0 {
+		params.Add("cl_ord_id", r.ClientOrderID)
+	}
+	return params
+}
+
+// KrakenSpotClosedOrdersRequest filters the orders returned by
+// GetClosedOrders. Zero values are not sent.
+type KrakenSpotClosedOrdersRequest struct {
+	// Trades includes the IDs of each order's trades.
+	Trades        bool
+	UserRef       int32
+	ClientOrderID string
+	// Start and End bound the results, exclusively, by a Unix timestamp or
+	// an order transaction ID.
+	Start string
+	End   string
+	// Offset is the number of results to skip, to page through Count results
+	// 50 at a time.
+	Offset    int
+	CloseTime KrakenSpotCloseTime
+}
+
+func (r KrakenSpotClosedOrdersRequest) params() url.Values {
+	params := KrakenSpotOpenOrdersRequest{Trades: r.Trades, UserRef: r.UserRef, ClientOrderID: r.ClientOrderID}.params()
+	if len(r.Start) > 0 {
+		params.Add("start", r.Start)
+	}
+	if len(r.End) > 0 {
+		params.Add("end", r.End)
+	}
+	if r.Offset > 0 {
+		params.Add("ofs", strconv.Itoa(r.Offset))
+	}
+	if len(r.CloseTime) > 0 {
+		params.Add("closetime", string(r.CloseTime))
+	}
+	return params
+}
+
+// KrakenSpotTradesHistoryRequest filters the trades returned by
+// GetTradesHistory. Zero values are not sent.
+type KrakenSpotTradesHistoryRequest struct {
+	Type KrakenSpotTradeHistoryType
+	// Trades includes the IDs of the trades related to each position.
+	Trades bool
+	// Start and End bound the results, exclusively, by a Unix timestamp or
+	// a trade transaction ID.
+	Start string
+	End   string
+	// Offset is the number of results to skip, to page through Count results
+	// 50 at a time.
+	Offset int
+	// SplitTakerTrades returns one fill per maker order matched by a taker
+	// order instead of Kraken's default of one consolidated fill.
+	SplitTakerTrades bool
+	// Ledgers includes the IDs of the ledger entries of each trade.
+	Ledgers bool
+}
+
+func (r KrakenSpotTradesHistoryRequest) params() url.Values {
+	params := url.Values{}
+	if len(r.Type) > 0 {
+		params.Add("type", string(r.Type))
+	}
+	if r.Trades {
+		params.Add("trades", "true")
+	}
+	if len(r.Start) > 0 {
+		params.Add("start", r.Start)
+	}
+	if len(r.End) > 0 {
+		params.Add("end", r.End)
+	}
+	if r.Offset > 0 {
+		params.Add("ofs", strconv.Itoa(r.Offset))
+	}
+	if r.SplitTakerTrades {
+		params.Add("consolidate_taker", "false")
+	}
+	if r.Ledgers {
+		params.Add("ledgers", "true")
+	}
+	return params
+}
+
+// KrakenSpotLedgersRequest filters the entries returned by GetLedgers. Zero
+// values are not sent.
+type KrakenSpotLedgersRequest struct {
+	Assets     []string
+	AssetClass KrakenAssetClass
+	Type       KrakenSpotLedgerType
+	// Start and End bound the results, exclusively, by a Unix timestamp or
+	// a ledger ID.
+	Start string
+	End   string
+	// Offset is the number of results to skip, to page through the results
+	// 50 at a time.
+	Offset int
+	// WithoutCount skips counting the matching entries, which speeds up
+	// requests on large ledgers; Count is then zero.
+	WithoutCount bool
+}
+
+func (r KrakenSpotLedgersRequest) params() url.Values {
+	params := url.Values{}
+	if len(r.Assets) > 0 {
+		params.Add("asset", strings.Join(r.Assets, ","))
+	}
+	if len(r.AssetClass) > 0 {
+		params.Add("aclass", string(r.AssetClass))
+	}
+	if len(r.Type) > 0 {
+		params.Add("type", string(r.Type))
+	}
+	if len(r.Start) > 0 {
+		params.Add("start", r.Start)
+	}
+	if len(r.End) > 0 {
+		params.Add("end", r.End)
+	}
+	if r.Offset > 0 {
+		params.Add("ofs", strconv.Itoa(r.Offset))
+	}
+	if r.WithoutCount {
+		params.Add("without_count", "true")
+	}
+	return params
+}
@@ -0,0 +1,295 @@
+package kraken
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseKrakenSpotOrderPrice(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want KrakenSpotOrderPrice
+	}{
+		{"", KrakenSpotOrderPrice{}},
+		{"0", KrakenSpotOrderPrice{}},
+		{"27500.0", NewKrakenSpotPrice(MustParseDecimal("27500.0"))},
+		{"+50", NewKrakenSpotPriceOffset(KrakenSpotPricePlus, MustParseDecimal("50"))},
+		{"-150.5", NewKrakenSpotPriceOffset(KrakenSpotPriceMinus, MustParseDecimal("150.5"))},
+		{"#50", NewKrakenSpotPriceOffset(KrakenSpotPriceAuto, MustParseDecimal("50"))},
+		{"2%", NewKrakenSpotPricePercent(KrakenSpotPriceAbsolute, MustParseDecimal("2"))},
+		{"-2.5%", NewKrakenSpotPricePercent(KrakenSpotPriceMinus, MustParseDecimal("2.5"))},
+	} {
+		got, err := ParseKrakenSpotOrderPrice(tt.in)
+		if err != nil {
+			t.Errorf("ParseKrakenSpotOrderPrice(%q) error = %v", tt.in, err)
+			continue
+		}
+		if got.Offset != tt.want.Offset || got.Percent != tt.want.Percent || !got.Value.Equal(tt.want.Value) {
+			t.Errorf("ParseKrakenSpotOrderPrice(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if got.String() != tt.want.String() {
+			t.Errorf("ParseKrakenSpotOrderPrice(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"+", "%", "+-5", "--5", "#+5", "5%%", "abc"} {
+		if p, err := ParseKrakenSpotOrderPrice(in); err == nil {
+			t.Errorf("ParseKrakenSpotOrderPrice(%q) = %s, want an error", in, p)
+		}
+	}
+}
+
+func TestKrakenSpotOrderDescriptionJSON(t *testing.T) {
+	data := `{"pair":"XBTUSD","type":"sell","ordertype":"new-type","price":"#50","price2":"2%","leverage":"none","order":"sell 1.25 XBTUSD @ trailing stop #50","close":""}`
+	var d KrakenSpotOrderDescription
+	if err := json.Unmarshal([]byte(data), &d); err != nil {
+		t.Fatalf("Unmarshal error = %v", err)
+	}
+	if d.Price.String() != "#50" || d.Price2.String() != "2%" {
+		t.Errorf("Price = %s, Price2 = %s, want #50 and 2%%", d.Price, d.Price2)
+	}
+	if d.OrderType != "new-type" || d.OrderType.Valid() {
+		t.Errorf("OrderType = %q, Valid() = %t", d.OrderType, d.OrderType.Valid())
+	}
+
+	if err := json.Unmarshal([]byte(`{"price":27500.5,"price2":null}`), &d); err != nil {
+		t.Fatalf("Unmarshal error = %v", err)
+	}
+	if d.Price.String() != "27500.5" || !d.Price2.IsZero() {
+		t.Errorf("Price = %s, Price2 = %s, want 27500.5 and no price", d.Price, d.Price2)
+	}
+}
+
+// xbtusdInfo is the asset pair info of XBTUSD, trimmed to the fields used to
+// round orders.
+var xbtusdInfo = KrakenSpotAssetPairInfo{
+	AlternateName: "XBTUSD",
+	CostDecimals:  5,
+	PairDecimals:  1,
+	LotDecimals:   8,
+	OrderMin:      MustParseDecimal("0.0001"),
+	CostMin:       MustParseDecimal("0.5"),
+}
+
+func TestRoundOrder(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		order   KrakenSpotOrderRequest
+		volume  string
+		price   string
+		price2  string
+		close   string
+		wantErr bool
+	}{
+		{
+			name:   "limit",
+			order:  KrakenSpotOrderRequest{OrderType: KrakenOrderTypeLimit, Volume: MustParseDecimal("0.123456789"), Price: NewKrakenSpotPrice(MustParseDecimal("30000.06"))},
+			volume: "0.12345678", price: "30000.1",
+		},
+		{
+			name:   "volume below the minimum",
+			order:  KrakenSpotOrderRequest{OrderType: KrakenOrderTypeLimit, Volume: MustParseDecimal("0.00009"), Price: NewKrakenSpotPrice(MustParseDecimal("30000"))},
+			volume: "0.00009", price: "30000",
+			wantErr: true,
+		},
+		{
+			name:   "cost below the minimum",
+			order:  KrakenSpotOrderRequest{OrderType: KrakenOrderTypeLimit, Volume: MustParseDecimal("0.0001"), Price: NewKrakenSpotPrice(MustParseDecimal("1000"))},
+			volume: "0.0001", price: "1000",
+			wantErr: true,
+		},
+		{
+			name:   "volume in quote",
+			order:  KrakenSpotOrderRequest{OrderType: KrakenOrderTypeMarket, Volume: MustParseDecimal("20.12345678"), Flags: []KrakenSpotOrderFlag{KrakenSpotOrderFlagVolumeInQuote}},
+			volume: "20.12345",
+		},
+		{
+			name:    "volume in quote below the minimum cost",
+			order:   KrakenSpotOrderRequest{OrderType: KrakenOrderTypeMarket, Volume: MustParseDecimal("0.4"), Flags: []KrakenSpotOrderFlag{KrakenSpotOrderFlagVolumeInQuote}},
+			volume:  "0.4",
+			wantErr: true,
+		},
+		{
+			name:   "settle-position with volume 0",
+			order:  KrakenSpotOrderRequest{OrderType: KrakenOrderTypeSettlePosition},
+			volume: "0",
+		},
+		{
+			name:   "offset and percent prices",
+			order:  KrakenSpotOrderRequest{OrderType: KrakenOrderTypeStopLossLimit, Volume: MustParseDecimal("0.0001"), Price: NewKrakenSpotPriceOffset(KrakenSpotPriceAuto, MustParseDecimal("50.06")), Price2: NewKrakenSpotPricePercent(KrakenSpotPriceMinus, MustParseDecimal("1.25"))},
+			volume: "0.0001", price: "#50.1", price2: "-1.25%",
+		},
+		{
+			name: "close order",
+			order: KrakenSpotOrderRequest{OrderType: KrakenOrderTypeLimit, Volume: MustParseDecimal("1"), Price: NewKrakenSpotPrice(MustParseDecimal("30000")),
+				Close: &KrakenSpotCloseOrder{OrderType: KrakenOrderTypeStopLoss, Price: NewKrakenSpotPrice(MustParseDecimal("29000.04"))}},
+			volume: "1", price: "30000", close: "29000.0",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.order
+			got, err := xbtusdInfo.RoundOrder(tt.order)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RoundOrder() error = %v, want error %t", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidArguments) {
+				t.Errorf("RoundOrder() error = %v, want ErrInvalidArguments", err)
+			}
+			if got.Volume.String() != tt.volume || got.Price.String() != tt.price || got.Price2.String() != tt.price2 {
+				t.Errorf("RoundOrder() volume %s, price %q, price2 %q, want %s, %q, %q", got.Volume, got.Price, got.Price2, tt.volume, tt.price, tt.price2)
+			}
+			if got.Close != nil && got.Close.Price.String() != tt.close {
+				t.Errorf("RoundOrder() close price %s, want %s", got.Close.Price, tt.close)
+			}
+			if original.Close != nil && original.Close == got.Close {
+				t.Error("RoundOrder() modified the close order of its argument")
+			}
+		})
+	}
+}
+
+func TestKrakenSpotOrderRequestParams(t *testing.T) {
+	deadline := time.Date(2023, 7, 6, 18, 50, 48, 500000000, time.UTC)
+	for _, tt := range []struct {
+		name    string
+		order   KrakenSpotOrderRequest
+		want    url.Values
+		wantErr bool
+	}{
+		{
+			name:  "volume in quote",
+			order: KrakenSpotOrderRequest{Pair: "XBTUSD", Side: KrakenSideBuy, OrderType: KrakenOrderTypeMarket, Volume: MustParseDecimal("20.12"), Flags: []KrakenSpotOrderFlag{KrakenSpotOrderFlagVolumeInQuote, KrakenSpotOrderFlagFeeInQuote}},
+			want:  url.Values{"pair": {"XBTUSD"}, "type": {"buy"}, "ordertype": {"market"}, "volume": {"20.12"}, "oflags": {"viqc,fciq"}},
+		},
+		{
+			name:  "settle-position with volume 0",
+			order: KrakenSpotOrderRequest{Pair: "XBTUSD", Side: KrakenSideSell, OrderType: KrakenOrderTypeSettlePosition, Leverage: 2},
+			want:  url.Values{"pair": {"XBTUSD"}, "type": {"sell"}, "ordertype": {"settle-position"}, "volume": {"0"}, "leverage": {"2"}},
+		},
+		{
+			name: "offset and percent prices",
+			order: KrakenSpotOrderRequest{Pair: "XBTUSD", Side: KrakenSideSell, OrderType: KrakenOrderTypeStopLossLimit, Volume: MustParseDecimal("1.25"),
+				Price: NewKrakenSpotPriceOffset(KrakenSpotPriceAuto, MustParseDecimal("50")), Price2: NewKrakenSpotPricePercent(KrakenSpotPriceMinus, MustParseDecimal("2"))},
+			want: url.Values{"pair": {"XBTUSD"}, "type": {"sell"}, "ordertype": {"stop-loss-limit"}, "volume": {"1.25"}, "price": {"#50"}, "price2": {"-2%"}},
+		},
+		{
+			name: "close order",
+			order: KrakenSpotOrderRequest{Pair: "XBTUSD", Side: KrakenSideBuy, OrderType: KrakenOrderTypeLimit, Volume: MustParseDecimal("1"), Price: NewKrakenSpotPrice(MustParseDecimal("30000")),
+				Close:         &KrakenSpotCloseOrder{OrderType: KrakenOrderTypeStopLossLimit, Price: NewKrakenSpotPriceOffset(KrakenSpotPriceMinus, MustParseDecimal("500")), Price2: NewKrakenSpotPrice(MustParseDecimal("29000"))},
+				ClientOrderID: "order-1", Deadline: deadline, Validate: true},
+			want: url.Values{"pair": {"XBTUSD"}, "type": {"buy"}, "ordertype": {"limit"}, "volume": {"1"}, "price": {"30000"},
+				"close[ordertype]": {"stop-loss-limit"}, "close[price]": {"-500"}, "close[price2]": {"29000"},
+				"cl_ord_id": {"order-1"}, "deadline": {"2023-07-06T18:50:48.5Z"}, "validate": {"true"}},
+		},
+		{
+			name:    "invalid side",
+			order:   KrakenSpotOrderRequest{Pair: "XBTUSD", OrderType: KrakenOrderTypeMarket, Volume: MustParseDecimal("1")},
+			wantErr: true,
+		},
+		{
+			name:    "invalid close order type",
+			order:   KrakenSpotOrderRequest{Pair: "XBTUSD", Side: KrakenSideBuy, OrderType: KrakenOrderTypeMarket, Volume: MustParseDecimal("1"), Close: &KrakenSpotCloseOrder{}},
+			wantErr: true,
+		},
+		{
+			name:    "userref and client order ID",
+			order:   KrakenSpotOrderRequest{Pair: "XBTUSD", Side: KrakenSideBuy, OrderType: KrakenOrderTypeMarket, Volume: MustParseDecimal("1"), UserRef: 1, ClientOrderID: "order-1"},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.order.params()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArguments) {
+					t.Errorf("params() error = %v, want ErrInvalidArguments", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("params() error = %v", err)
+			}
+			if got.Encode() != tt.want.Encode() {
+				t.Errorf("params() = %s, want %s", got.Encode(), tt.want.Encode())
+			}
+		})
+	}
+}
+
+// equalJSON reports whether a and b encode the same JSON value.
+func equalJSON(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var x, y any
+	if err := json.Unmarshal(a, &x); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", a, err)
+	}
+	if err := json.Unmarshal(b, &y); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", b, err)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func TestKrakenSpotOrderBatchRequestPayload(t *testing.T) {
+	buy := KrakenSpotOrderRequest{Pair: "XBTUSD", Side: KrakenSideBuy, OrderType: KrakenOrderTypeLimit, Volume: MustParseDecimal("1.25"),
+		Price: NewKrakenSpotPrice(MustParseDecimal("27500")), ClientOrderID: "order-1"}
+	sell := KrakenSpotOrderRequest{Pair: "xbt/usd", Side: KrakenSideSell, OrderType: KrakenOrderTypeStopLossLimit, Volume: MustParseDecimal("1.25"),
+		Price: NewKrakenSpotPriceOffset(KrakenSpotPriceAuto, MustParseDecimal("50")), Price2: NewKrakenSpotPricePercent(KrakenSpotPriceMinus, MustParseDecimal("2")),
+		Flags: []KrakenSpotOrderFlag{KrakenSpotOrderFlagPostOnly}, Close: &KrakenSpotCloseOrder{OrderType: KrakenOrderTypeLimit, Price: NewKrakenSpotPrice(MustParseDecimal("30000"))}}
+
+	batch := KrakenSpotOrderBatchRequest{
+		Orders:   []KrakenSpotOrderRequest{buy, sell},
+		Deadline: time.Date(2023, 7, 6, 18, 50, 48, 500000000, time.UTC),
+		Validate: true,
+	}
+	payload, err := batch.payload()
+	if err != nil {
+		t.Fatalf("payload() error = %v", err)
+	}
+	got, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Marshal error = %v", err)
+	}
+	want := `{
+		"pair": "XBTUSD",
+		"orders": [
+			{"ordertype": "limit", "type": "buy", "volume": "1.25", "price": "27500", "cl_ord_id": "order-1"},
+			{"ordertype": "stop-loss-limit", "type": "sell", "volume": "1.25", "price": "#50", "price2": "-2%", "oflags": "post",
+			 "close": {"ordertype": "limit", "price": "30000"}}
+		],
+		"deadline": "2023-07-06T18:50:48.5Z",
+		"validate": true
+	}`
+	if !equalJSON(t, got, []byte(want)) {
+		t.Errorf("payload() = %s, want %s", got, want)
+	}
+
+	tooMany := make([]KrakenSpotOrderRequest, 16)
+	for i := range tooMany {
+		tooMany[i] = buy
+	}
+	withDeadline, withValidate, otherPair := buy, buy, buy
+	withDeadline.Deadline = batch.Deadline
+	withValidate.Validate = true
+	otherPair.Pair = "ETHUSD"
+	for _, tt := range []struct {
+		name   string
+		orders []KrakenSpotOrderRequest
+	}{
+		{"one order", []KrakenSpotOrderRequest{buy}},
+		{"16 orders", tooMany},
+		{"mixed pairs", []KrakenSpotOrderRequest{buy, otherPair}},
+		{"deadline on an order", []KrakenSpotOrderRequest{buy, withDeadline}},
+		{"validate on an order", []KrakenSpotOrderRequest{withValidate, buy}},
+		{"invalid order", []KrakenSpotOrderRequest{buy, {Pair: "XBTUSD"}}},
+	} {
+		if _, err := (KrakenSpotOrderBatchRequest{Orders: tt.orders}).payload(); !errors.Is(err, ErrInvalidArguments) {
+			t.Errorf("%s: payload() error = %v, want ErrInvalidArguments", tt.name, err)
+		}
+	}
+	if _, err := (KrakenSpotOrderBatchRequest{Orders: tooMany[:15]}).payload(); err != nil {
+		t.Errorf("payload() of 15 orders error = %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// KrakenSpotPairNames resolves any of the names a pair is known by (its
+// canonical name such as "XXBTZUSD", its altname "XBTUSD" or its wsname
+// "XBT/USD") to the canonical name Kraken keys its results by.
+type KrakenSpotPairNames map[string]string
+
+func NewKrakenSpotPairNames(pairs map[string]KrakenSpotAssetPairInfo) KrakenSpotPairNames {
+	names := make(KrakenSpotPairNames, 3*len(pairs))
+	for canonical, info := range pairs {
+		for _, name := range []string{canonical, info.AlternateName, info.WebsocketName} {
+			if len(name) > 0 {
+				names[normalizePairName(name)] = canonical
+			}
+		}
+	}
+	return names
+}
+
+// Canonical returns the canonical name of pair.
+func (n KrakenSpotPairNames) Canonical(pair string) (string, bool) {
+	canonical, ok := n[normalizePairName(pair)]
+	return canonical, ok
+}
+
+func normalizePairName(pair string) string {
+	return strings.ToUpper(strings.ReplaceAll(pair, "/", ""))
+}
+
+// resolvePairKey finds the key of pair in a result without further requests:
+// the key matching pair exactly or up to case and "/" separators, or else the
+// only pair key in the result, since single pair endpoints are keyed by the
+// canonical name whatever name the request used. The "last" cursor is not a
+// pair key.
+func resolvePairKey[T any](result map[string]T, pair string) (string, bool) {
+	if _, ok := result[pair]; ok && pair != "last" {
+		return pair, true
+	}
+
+	normalized := normalizePairName(pair)
+	only, count := "", 0
+	for key := range result {
+		if key == "last" {
+			continue
+		}
+		if normalizePairName(key) == normalized {
+			return key, true
+		}
+		only = key
+		count++
+	}
+	if count == 1 {
+		return only, true
+	}
+	return "", false
+}
+
+// lookupPairResult returns the entry for pair in result. Names that cannot be
+// matched directly against several pair keys are resolved through the
+// client's asset pair metadata; a result without pair keys has no entry to
+// resolve.
+func lookupPairResult[T any](ctx context.Context, c *KrakenSpotHttpClient, result map[string]T, pair string) (T, error) {
+	if key, ok := resolvePairKey(result, pair); ok {
+		return result[key], nil
+	}
+
+	var zero T
+	// resolvePairKey takes the only pair key, so an unresolved result has two
+	// or more pair keys or none at all
+	if _, hasLast := result["last"]; len(result) == 0 || (hasLast && len(result) == 1) {
+		return zero, fmt.Errorf("%w: %s", ErrPairNotFound, pair)
+	}
+
+	names, err := c.pairNames(ctx)
+	if err != nil {
+		return zero, err
+	}
+	if canonical, ok := names.Canonical(pair); ok {
+		if entry, ok := result[canonical]; ok {
+			return entry, nil
+		}
+	}
+	return zero, fmt.Errorf("%w: %s", ErrPairNotFound, pair)
+}
+
+// pairNames returns the pair names of every asset pair, fetched once and cached.
+func (c *KrakenSpotHttpClient) pairNames(ctx context.Context) (KrakenSpotPairNames, error) {
+	c.pairNamesMu.Lock()
+	defer c.pairNamesMu.Unlock()
+
+	if c.pairNamesCache == nil {
+		pairs, err := c.GetAssetPairsWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.pairNamesCache = NewKrakenSpotPairNames(pairs)
+	}
+	return c.pairNamesCache, nil
+}
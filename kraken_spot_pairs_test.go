@@ -0,0 +1,92 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var testAssetPairs = map[string]KrakenSpotAssetPairInfo{
+	"XXBTZUSD": {AlternateName: "XBTUSD", WebsocketName: "XBT/USD"},
+	"XETHZUSD": {AlternateName: "ETHUSD", WebsocketName: "ETH/USD"},
+	"DOTUSD":   {AlternateName: "DOTUSD", WebsocketName: "DOT/USD"},
+}
+
+func TestNewKrakenSpotPairNames(t *testing.T) {
+	names := NewKrakenSpotPairNames(testAssetPairs)
+	for _, tt := range []struct {
+		pair string
+		want string
+	}{
+		{"XXBTZUSD", "XXBTZUSD"},
+		{"XBTUSD", "XXBTZUSD"},
+		{"XBT/USD", "XXBTZUSD"},
+		{"xbt/usd", "XXBTZUSD"},
+		{"ethusd", "XETHZUSD"},
+		{"DOTUSD", "DOTUSD"},
+	} {
+		if got, ok := names.Canonical(tt.pair); !ok || got != tt.want {
+			t.Errorf("Canonical(%s) = %s, %t, want %s", tt.pair, got, ok, tt.want)
+		}
+	}
+	if got, ok := names.Canonical("XBTEUR"); ok {
+		t.Errorf("Canonical(XBTEUR) = %s, want not found", got)
+	}
+}
+
+func TestResolvePairKey(t *testing.T) {
+	several := map[string]int{"XXBTZUSD": 1, "XETHZUSD": 2, "last": 3}
+	for _, tt := range []struct {
+		name   string
+		result map[string]int
+		pair   string
+		want   string
+		ok     bool
+	}{
+		{"exact", several, "XXBTZUSD", "XXBTZUSD", true},
+		{"lower case", several, "xxbtzusd", "XXBTZUSD", true},
+		{"separator", map[string]int{"XBTUSD": 1, "ETHUSD": 2}, "xbt/usd", "XBTUSD", true},
+		{"only pair key", map[string]int{"XXBTZUSD": 1, "last": 3}, "XBT/USD", "XXBTZUSD", true},
+		{"several pair keys", several, "XBTUSD", "", false},
+		{"last only", map[string]int{"last": 3}, "XBTUSD", "", false},
+		{"last is not a pair", several, "last", "", false},
+	} {
+		got, ok := resolvePairKey(tt.result, tt.pair)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: resolvePairKey(%s) = %q, %t, want %q, %t", tt.name, tt.pair, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLookupPairResult(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":{"altname":"XBTUSD","wsname":"XBT/USD"},"XETHZUSD":{"altname":"ETHUSD","wsname":"ETH/USD"}}}`))
+	}))
+	defer srv.Close()
+	c := NewKrakenSpotHttpClient(WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	// no pair keys: nothing to resolve, so no metadata is fetched
+	if _, err := lookupPairResult(ctx, c, map[string]int{"last": 3}, "XBTUSD"); !errors.Is(err, ErrPairNotFound) {
+		t.Errorf("lookupPairResult(last only) error = %v, want ErrPairNotFound", err)
+	}
+	if len(paths) > 0 {
+		t.Errorf("lookupPairResult(last only) requested %v", paths)
+	}
+
+	// several pair keys: the altname is resolved through the asset pairs
+	several := map[string]int{"XXBTZUSD": 1, "XETHZUSD": 2, "last": 3}
+	if got, err := lookupPairResult(ctx, c, several, "XBTUSD"); err != nil || got != 1 {
+		t.Errorf("lookupPairResult(XBTUSD) = %d, %v, want 1", got, err)
+	}
+	if _, err := lookupPairResult(ctx, c, several, "DOTUSD"); !errors.Is(err, ErrPairNotFound) {
+		t.Errorf("lookupPairResult(DOTUSD) error = %v, want ErrPairNotFound", err)
+	}
+	if len(paths) != 1 || paths[0] != "/public/AssetPairs" {
+		t.Errorf("requested %v, want the asset pairs once", paths)
+	}
+}
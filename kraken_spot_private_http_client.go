@@ -0,0 +1,655 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Private endpoints of KrakenSpotHttpClient. They require a client created
+// with WithCredentials and return ErrNoCredentials otherwise.
+
+// GetBalance returns the balance of every asset in the account, keyed by
+// Kraken's asset names.
+func (c *KrakenSpotHttpClient) GetBalance() (map[string]Decimal, error) {
+	return c.GetBalanceWithContext(context.Background())
+}
+
+func (c *KrakenSpotHttpClient) GetBalanceWithContext(ctx context.Context) (map[string]Decimal, error) {
+	body, err := c.post(ctx, "/private/Balance", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotBalanceResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.Error) > 0 {
+		return nil, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// GetExtendedBalance returns the balance, credit and amount held in open
+// orders of every asset in the account, keyed by Kraken's asset names.
+func (c *KrakenSpotHttpClient) GetExtendedBalance() (map[string]KrakenSpotExtendedBalance, error) {
+	return c.GetExtendedBalanceWithContext(context.Background())
+}
+
+func (c *KrakenSpotHttpClient) GetExtendedBalanceWithContext(ctx context.Context) (map[string]KrakenSpotExtendedBalance, error) {
+	body, err := c.post(ctx, "/private/BalanceEx", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotExtendedBalanceResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.Error) > 0 {
+		return nil, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// GetTradeBalance returns the margin summary of the account expressed in
+// asset. An empty asset is not sent and Kraken defaults to ZUSD.
+func (c *KrakenSpotHttpClient) GetTradeBalance(asset string) (KrakenSpotTradeBalance, error) {
+	return c.GetTradeBalanceWithContext(context.Background(), asset)
+}
+
+func (c *KrakenSpotHttpClient) GetTradeBalanceWithContext(ctx context.Context, asset string) (KrakenSpotTradeBalance, error) {
+	params := url.Values{}
+	if len(asset) > 0 {
+		params.Add("asset", asset)
+	}
+
+	body, err := c.post(ctx, "/private/TradeBalance", params, true)
+	if err != nil {
+		return KrakenSpotTradeBalance{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotTradeBalanceResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotTradeBalance{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotTradeBalance{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// AddOrder places order. It is not retried unless the retry policy sets
+// RetryNonIdempotent; KrakenSpotAssetPairInfo.RoundOrder prepares an order
+// against the pair's precision and minimums.
+func (c *KrakenSpotHttpClient) AddOrder(order KrakenSpotOrderRequest) (KrakenSpotAddOrderResult, error) {
+	return c.AddOrderWithContext(context.Background(), order)
+}
+
+func (c *KrakenSpotHttpClient) AddOrderWithContext(ctx context.Context, order KrakenSpotOrderRequest) (KrakenSpotAddOrderResult, error) {
+	params, err := order.params()
+	if err != nil {
+		return KrakenSpotAddOrderResult{}, err
+	}
+
+	body, err := c.post(ctx, "/private/AddOrder", params, false)
+	if err != nil {
+		return KrakenSpotAddOrderResult{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotAddOrderResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotAddOrderResult{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotAddOrderResult{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// AmendOrder changes the quantity or prices of an open order in place. Like
+// AddOrder it is not retried unless the retry policy sets RetryNonIdempotent.
+func (c *KrakenSpotHttpClient) AmendOrder(amend KrakenSpotAmendOrderRequest) (KrakenSpotAmendOrderResult, error) {
+	return c.AmendOrderWithContext(context.Background(), amend)
+}
+
+func (c *KrakenSpotHttpClient) AmendOrderWithContext(ctx context.Context, amend KrakenSpotAmendOrderRequest) (KrakenSpotAmendOrderResult, error) {
+	params, err := amend.params()
+	if err != nil {
+		return KrakenSpotAmendOrderResult{}, err
+	}
+
+	body, err := c.post(ctx, "/private/AmendOrder", params, false)
+	if err != nil {
+		return KrakenSpotAmendOrderResult{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotAmendOrderResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotAmendOrderResult{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotAmendOrderResult{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// EditOrder cancels an open order and replaces it with a new one. Like
+// AddOrder it is not retried unless the retry policy sets RetryNonIdempotent.
+func (c *KrakenSpotHttpClient) EditOrder(edit KrakenSpotEditOrderRequest) (KrakenSpotEditOrderResult, error) {
+	return c.EditOrderWithContext(context.Background(), edit)
+}
+
+func (c *KrakenSpotHttpClient) EditOrderWithContext(ctx context.Context, edit KrakenSpotEditOrderRequest) (KrakenSpotEditOrderResult, error) {
+	params, err := edit.params()
+	if err != nil {
+		return KrakenSpotEditOrderResult{}, err
+	}
+
+	body, err := c.post(ctx, "/private/EditOrder", params, false)
+	if err != nil {
+		return KrakenSpotEditOrderResult{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotEditOrderResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotEditOrderResult{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotEditOrderResult{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// CancelOrder cancels the open order with the transaction ID txid.
+func (c *KrakenSpotHttpClient) CancelOrder(txid string) (KrakenSpotCancelOrderResult, error) {
+	return c.CancelOrderWithContext(context.Background(), txid)
+}
+
+func (c *KrakenSpotHttpClient) CancelOrderWithContext(ctx context.Context, txid string) (KrakenSpotCancelOrderResult, error) {
+	params := url.Values{}
+	params.Add("txid", txid)
+	return c.cancelOrder(ctx, "/private/CancelOrder", params)
+}
+
+// CancelOrderByUserRef cancels every open order placed with the user
+// reference userref.
+func (c *KrakenSpotHttpClient) CancelOrderByUserRef(userref int32) (KrakenSpotCancelOrderResult, error) {
+	return c.CancelOrderByUserRefWithContext(context.Background(), userref)
+}
+
+func (c *KrakenSpotHttpClient) CancelOrderByUserRefWithContext(ctx context.Context, userref int32) (KrakenSpotCancelOrderResult, error) {
+	params := url.Values{}
+	params.Add("txid", strconv.FormatInt(int64(userref), 10))
+	return c.cancelOrder(ctx, "/private/CancelOrder", params)
+}
+
+// CancelOrderByClientOrderID cancels the open order placed with the client
+// order ID clOrdID.
+func (c *KrakenSpotHttpClient) CancelOrderByClientOrderID(clOrdID string) (KrakenSpotCancelOrderResult, error) {
+	return c.CancelOrderByClientOrderIDWithContext(context.Background(), clOrdID)
+}
+
+func (c *KrakenSpotHttpClient) CancelOrderByClientOrderIDWithContext(ctx context.Context, clOrdID string) (KrakenSpotCancelOrderResult, error) {
+	params := url.Values{}
+	params.Add("cl_ord_id", clOrdID)
+	return c.cancelOrder(ctx, "/private/CancelOrder", params)
+}
+
+// CancelAllOrders cancels every open order of the account.
+func (c *KrakenSpotHttpClient) CancelAllOrders() (KrakenSpotCancelOrderResult, error) {
+	return c.CancelAllOrdersWithContext(context.Background())
+}
+
+func (c *KrakenSpotHttpClient) CancelAllOrdersWithContext(ctx context.Context) (KrakenSpotCancelOrderResult, error) {
+	return c.cancelOrder(ctx, "/private/CancelAll", nil)
+}
+
+// cancelOrder is not retried unless the retry policy sets RetryNonIdempotent:
+// a retry after a cancellation that went through reports an unknown order.
+func (c *KrakenSpotHttpClient) cancelOrder(ctx context.Context, endpoint string, params url.Values) (KrakenSpotCancelOrderResult, error) {
+	body, err := c.post(ctx, endpoint, params, false)
+	if err != nil {
+		return KrakenSpotCancelOrderResult{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotCancelOrderResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotCancelOrderResult{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotCancelOrderResult{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// CancelAllOrdersAfter arms a dead man's switch that cancels every open order
+// of the account once timeout elapses, unless it is called again before then.
+// The timeout is rounded down to whole seconds and 0 disables the switch; a
+// negative timeout or one shorter than a second is rejected, since it would
+// disable the switch instead of arming it.
+func (c *KrakenSpotHttpClient) CancelAllOrdersAfter(timeout time.Duration) (KrakenSpotCancelAllOrdersAfterResult, error) {
+	return c.CancelAllOrdersAfterWithContext(context.Background(), timeout)
+}
+
+func (c *KrakenSpotHttpClient) CancelAllOrdersAfterWithContext(ctx context.Context, timeout time.Duration) (KrakenSpotCancelAllOrdersAfterResult, error) {
+	if timeout < 0 || (timeout > 0 && timeout < time.Second) {
+		return KrakenSpotCancelAllOrdersAfterResult{}, fmt.Errorf("%w: timeout %v is not 0 or at least a second", ErrInvalidArguments, timeout)
+	}
+
+	params := url.Values{}
+	params.Add("timeout", strconv.FormatInt(int64(timeout/time.Second), 10))
+
+	// resetting the timer is safe to repeat, so the request may be retried
+	body, err := c.post(ctx, "/private/CancelAllOrdersAfter", params, true)
+	if err != nil {
+		return KrakenSpotCancelAllOrdersAfterResult{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotCancelAllOrdersAfterResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotCancelAllOrdersAfterResult{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotCancelAllOrdersAfterResult{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// AddOrderBatch places between 2 and 15 orders on a single pair in one
+// request. Orders are placed independently: check the Err of each result,
+// which is in the position of its order in the batch. Like AddOrder it is not
+// retried unless the retry policy sets RetryNonIdempotent.
+func (c *KrakenSpotHttpClient) AddOrderBatch(batch KrakenSpotOrderBatchRequest) (KrakenSpotAddOrderBatchResult, error) {
+	return c.AddOrderBatchWithContext(context.Background(), batch)
+}
+
+func (c *KrakenSpotHttpClient) AddOrderBatchWithContext(ctx context.Context, batch KrakenSpotOrderBatchRequest) (KrakenSpotAddOrderBatchResult, error) {
+	payload, err := batch.payload()
+	if err != nil {
+		return KrakenSpotAddOrderBatchResult{}, err
+	}
+
+	body, err := c.postJSON(ctx, "/private/AddOrderBatch", payload, false)
+	if err != nil {
+		return KrakenSpotAddOrderBatchResult{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotAddOrderBatchResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotAddOrderBatchResult{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotAddOrderBatchResult{}, newKrakenSpotError(d.Error)
+	}
+
+	if len(d.Result.Orders) != len(batch.Orders) {
+		return d.Result, fmt.Errorf("kraken: batch of %d orders returned %d results", len(batch.Orders), len(d.Result.Orders))
+	}
+	return d.Result, nil
+}
+
+// CancelOrderBatch cancels up to 50 open orders in one request, identified by
+// transaction ID or user reference in txids and by client order ID in
+// clOrdIDs.
+func (c *KrakenSpotHttpClient) CancelOrderBatch(txids []string, clOrdIDs []string) (KrakenSpotCancelOrderResult, error) {
+	return c.CancelOrderBatchWithContext(context.Background(), txids, clOrdIDs)
+}
+
+func (c *KrakenSpotHttpClient) CancelOrderBatchWithContext(ctx context.Context, txids []string, clOrdIDs []string) (KrakenSpotCancelOrderResult, error) {
+	count := len(txids) + len(clOrdIDs)
+	if count == 0 || count > krakenSpotMaxBatchCancelOrders {
+		return KrakenSpotCancelOrderResult{}, fmt.Errorf("%w: a batch cancels 1 to %d orders, got %d",
+			ErrInvalidArguments, krakenSpotMaxBatchCancelOrders, count)
+	}
+
+	payload := map[string]any{}
+	if len(txids) > 0 {
+		orders := make([]map[string]string, len(txids))
+		for i, txid := range txids {
+			orders[i] = map[string]string{"txid": txid}
+		}
+		payload["orders"] = orders
+	}
+	if len(clOrdIDs) > 0 {
+		orders := make([]map[string]string, len(clOrdIDs))
+		for i, clOrdID := range clOrdIDs {
+			orders[i] = map[string]string{"cl_ord_id": clOrdID}
+		}
+		payload["cl_ord_ids"] = orders
+	}
+
+	body, err := c.postJSON(ctx, "/private/CancelOrderBatch", payload, false)
+	if err != nil {
+		return KrakenSpotCancelOrderResult{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotCancelOrderResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotCancelOrderResult{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotCancelOrderResult{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// GetOpenOrders returns the open orders of the account matching request,
+// keyed by transaction ID.
+func (c *KrakenSpotHttpClient) GetOpenOrders(request KrakenSpotOpenOrdersRequest) (map[string]KrakenSpotOrder, error) {
+	return c.GetOpenOrdersWithContext(context.Background(), request)
+}
+
+func (c *KrakenSpotHttpClient) GetOpenOrdersWithContext(ctx context.Context, request KrakenSpotOpenOrdersRequest) (map[string]KrakenSpotOrder, error) {
+	body, err := c.post(ctx, "/private/OpenOrders", request.params(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotOpenOrdersResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.Error) > 0 {
+		return nil, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result.Open, nil
+}
+
+// GetClosedOrders returns a page of up to 50 closed orders of the account
+// matching request, most recent first.
+func (c *KrakenSpotHttpClient) GetClosedOrders(request KrakenSpotClosedOrdersRequest) (KrakenSpotClosedOrders, error) {
+	return c.GetClosedOrdersWithContext(context.Background(), request)
+}
+
+func (c *KrakenSpotHttpClient) GetClosedOrdersWithContext(ctx context.Context, request KrakenSpotClosedOrdersRequest) (KrakenSpotClosedOrders, error) {
+	body, err := c.post(ctx, "/private/ClosedOrders", request.params(), true)
+	if err != nil {
+		return KrakenSpotClosedOrders{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotClosedOrdersResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotClosedOrders{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotClosedOrders{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// QueryOrders returns the orders with the given transaction IDs, up to 50,
+// keyed by transaction ID. trades includes the IDs of each order's trades.
+func (c *KrakenSpotHttpClient) QueryOrders(txids []string, trades bool) (map[string]KrakenSpotOrder, error) {
+	return c.QueryOrdersWithContext(context.Background(), txids, trades)
+}
+
+func (c *KrakenSpotHttpClient) QueryOrdersWithContext(ctx context.Context, txids []string, trades bool) (map[string]KrakenSpotOrder, error) {
+	if len(txids) == 0 {
+		return nil, fmt.Errorf("%w: at least one txid is required", ErrInvalidArguments)
+	}
+
+	params := url.Values{}
+	params.Add("txid", strings.Join(txids, ","))
+	if trades {
+		params.Add("trades", "true")
+	}
+
+	body, err := c.post(ctx, "/private/QueryOrders", params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotQueryOrdersResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.Error) > 0 {
+		return nil, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// GetTradesHistory returns a page of up to 50 trades of the account matching
+// request, most recent first.
+func (c *KrakenSpotHttpClient) GetTradesHistory(request KrakenSpotTradesHistoryRequest) (KrakenSpotTradesHistory, error) {
+	return c.GetTradesHistoryWithContext(context.Background(), request)
+}
+
+func (c *KrakenSpotHttpClient) GetTradesHistoryWithContext(ctx context.Context, request KrakenSpotTradesHistoryRequest) (KrakenSpotTradesHistory, error) {
+	body, err := c.post(ctx, "/private/TradesHistory", request.params(), true)
+	if err != nil {
+		return KrakenSpotTradesHistory{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotTradesHistoryResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotTradesHistory{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotTradesHistory{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// QueryTrades returns the trades with the given transaction IDs, up to 20,
+// keyed by transaction ID. trades includes the IDs of the trades related to
+// each position.
+func (c *KrakenSpotHttpClient) QueryTrades(txids []string, trades bool) (map[string]KrakenSpotFill, error) {
+	return c.QueryTradesWithContext(context.Background(), txids, trades)
+}
+
+func (c *KrakenSpotHttpClient) QueryTradesWithContext(ctx context.Context, txids []string, trades bool) (map[string]KrakenSpotFill, error) {
+	if len(txids) == 0 {
+		return nil, fmt.Errorf("%w: at least one txid is required", ErrInvalidArguments)
+	}
+
+	params := url.Values{}
+	params.Add("txid", strings.Join(txids, ","))
+	if trades {
+		params.Add("trades", "true")
+	}
+
+	body, err := c.post(ctx, "/private/QueryTrades", params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotQueryTradesResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.Error) > 0 {
+		return nil, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// GetOpenPositions returns the open margin positions of the account, keyed
+// by position transaction ID. An empty txids returns every position, and
+// doCalcs includes the current value and profit or loss of each position.
+func (c *KrakenSpotHttpClient) GetOpenPositions(txids []string, doCalcs bool) (map[string]KrakenSpotPosition, error) {
+	return c.GetOpenPositionsWithContext(context.Background(), txids, doCalcs)
+}
+
+func (c *KrakenSpotHttpClient) GetOpenPositionsWithContext(ctx context.Context, txids []string, doCalcs bool) (map[string]KrakenSpotPosition, error) {
+	params := url.Values{}
+	if len(txids) > 0 {
+		params.Add("txid", strings.Join(txids, ","))
+	}
+	if doCalcs {
+		params.Add("docalcs", "true")
+	}
+
+	body, err := c.post(ctx, "/private/OpenPositions", params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotOpenPositionsResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.Error) > 0 {
+		return nil, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// GetLedgers returns a page of up to 50 ledger entries of the account
+// matching request, most recent first.
+func (c *KrakenSpotHttpClient) GetLedgers(request KrakenSpotLedgersRequest) (KrakenSpotLedgers, error) {
+	return c.GetLedgersWithContext(context.Background(), request)
+}
+
+func (c *KrakenSpotHttpClient) GetLedgersWithContext(ctx context.Context, request KrakenSpotLedgersRequest) (KrakenSpotLedgers, error) {
+	body, err := c.post(ctx, "/private/Ledgers", request.params(), true)
+	if err != nil {
+		return KrakenSpotLedgers{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotLedgersResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotLedgers{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotLedgers{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// QueryLedgers returns the ledger entries with the given IDs, up to 20, keyed
+// by ledger ID.
+func (c *KrakenSpotHttpClient) QueryLedgers(ids []string) (map[string]KrakenSpotLedgerEntry, error) {
+	return c.QueryLedgersWithContext(context.Background(), ids)
+}
+
+func (c *KrakenSpotHttpClient) QueryLedgersWithContext(ctx context.Context, ids []string) (map[string]KrakenSpotLedgerEntry, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: at least one ledger id is required", ErrInvalidArguments)
+	}
+
+	params := url.Values{}
+	params.Add("id", strings.Join(ids, ","))
+
+	body, err := c.post(ctx, "/private/QueryLedgers", params, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotQueryLedgersResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.Error) > 0 {
+		return nil, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
+
+// GetTradeVolume returns the 30 day trading volume of the account and the
+// fees it pays on pairs. Fees are only returned for the pairs requested.
+func (c *KrakenSpotHttpClient) GetTradeVolume(pairs []string) (KrakenSpotTradeVolume, error) {
+	return c.GetTradeVolumeWithContext(context.Background(), pairs)
+}
+
+func (c *KrakenSpotHttpClient) GetTradeVolumeWithContext(ctx context.Context, pairs []string) (KrakenSpotTradeVolume, error) {
+	params := url.Values{}
+	if len(pairs) > 0 {
+		params.Add("pair", strings.Join(pairs, ","))
+	}
+
+	body, err := c.post(ctx, "/private/TradeVolume", params, true)
+	if err != nil {
+		return KrakenSpotTradeVolume{}, err
+	}
+
+	// Unmarshall the response body into a struct
+	var d KrakenSpotTradeVolumeResponse
+	err = json.Unmarshal(body, &d)
+	if err != nil {
+		return KrakenSpotTradeVolume{}, err
+	}
+
+	if len(d.Error) > 0 {
+		return KrakenSpotTradeVolume{}, newKrakenSpotError(d.Error)
+	}
+
+	return d.Result, nil
+}
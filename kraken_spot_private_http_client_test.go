@@ -0,0 +1,101 @@
+package kraken
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newPrivateTestClient returns a client with credentials whose requests are
+// answered with body by a test server, and the request bodies it received.
+func newPrivateTestClient(t *testing.T, body string) (*KrakenSpotHttpClient, *[]string) {
+	t.Helper()
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(data))
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return NewKrakenSpotHttpClient(WithBaseURL(srv.URL), WithCredentials("key", "c2VjcmV0")), &requests
+}
+
+func TestCancelAllOrdersAfter(t *testing.T) {
+	for _, tt := range []struct {
+		timeout time.Duration
+		want    string
+	}{
+		{0, "0"},
+		{time.Second, "1"},
+		{90*time.Second + 500*time.Millisecond, "90"},
+	} {
+		c, requests := newPrivateTestClient(t, `{"error":[],"result":{"currentTime":"2023-07-06T18:50:48Z","triggerTime":"0"}}`)
+		if _, err := c.CancelAllOrdersAfter(tt.timeout); err != nil {
+			t.Errorf("CancelAllOrdersAfter(%v) error = %v", tt.timeout, err)
+			continue
+		}
+		form, _ := url.ParseQuery((*requests)[0])
+		if got := form.Get("timeout"); got != tt.want {
+			t.Errorf("CancelAllOrdersAfter(%v) sent timeout %s, want %s", tt.timeout, got, tt.want)
+		}
+	}
+
+	for _, timeout := range []time.Duration{time.Millisecond, 500 * time.Millisecond, -time.Second} {
+		c, requests := newPrivateTestClient(t, `{"error":[],"result":{}}`)
+		if _, err := c.CancelAllOrdersAfter(timeout); !errors.Is(err, ErrInvalidArguments) {
+			t.Errorf("CancelAllOrdersAfter(%v) error = %v, want ErrInvalidArguments", timeout, err)
+		}
+		if len(*requests) > 0 {
+			t.Errorf("CancelAllOrdersAfter(%v) sent %q", timeout, *requests)
+		}
+	}
+}
+
+func TestCancelOrderBatch(t *testing.T) {
+	c, requests := newPrivateTestClient(t, `{"error":[],"result":{"count":3}}`)
+	result, err := c.CancelOrderBatch([]string{"OG5V2Y-RYKVL-DT3V3B", "1234"}, []string{"order-1"})
+	if err != nil {
+		t.Fatalf("CancelOrderBatch() error = %v", err)
+	}
+	if result.Count != 3 {
+		t.Errorf("Count = %d, want 3", result.Count)
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal([]byte((*requests)[0]), &body); err != nil {
+		t.Fatalf("body %s: %v", (*requests)[0], err)
+	}
+	if !equalJSON(t, body["orders"], []byte(`[{"txid":"OG5V2Y-RYKVL-DT3V3B"},{"txid":"1234"}]`)) {
+		t.Errorf("orders = %s", body["orders"])
+	}
+	if !equalJSON(t, body["cl_ord_ids"], []byte(`[{"cl_ord_id":"order-1"}]`)) {
+		t.Errorf("cl_ord_ids = %s", body["cl_ord_ids"])
+	}
+	if _, ok := body["nonce"]; !ok {
+		t.Error("body has no nonce")
+	}
+
+	c, requests = newPrivateTestClient(t, `{"error":[],"result":{"count":1}}`)
+	if _, err := c.CancelOrderBatch(nil, []string{"order-1"}); err != nil {
+		t.Fatalf("CancelOrderBatch() error = %v", err)
+	}
+	if strings.Contains((*requests)[0], `"orders"`) {
+		t.Errorf("body %s has orders without txids", (*requests)[0])
+	}
+
+	for _, count := range []int{0, 51} {
+		c, requests := newPrivateTestClient(t, `{"error":[],"result":{}}`)
+		if _, err := c.CancelOrderBatch(make([]string, count), nil); !errors.Is(err, ErrInvalidArguments) {
+			t.Errorf("CancelOrderBatch(%d orders) error = %v, want ErrInvalidArguments", count, err)
+		}
+		if len(*requests) > 0 {
+			t.Errorf("CancelOrderBatch(%d orders) sent a request", count)
+		}
+	}
+}
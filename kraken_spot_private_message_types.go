@@ -0,0 +1,355 @@
+package kraken
+
+type KrakenSpotBalanceResponse struct {
+	Error  []string           `json:"error"`
+	Result map[string]Decimal `json:"result"`
+}
+
+type KrakenSpotExtendedBalance struct {
+	Balance    Decimal `json:"balance"`
+	Credit     Decimal `json:"credit"`
+	CreditUsed Decimal `json:"credit_used"`
+	HoldTrade  Decimal `json:"hold_trade"`
+}
+
+// Available returns the amount that can be traded or withdrawn:
+// balance + credit - credit_used - hold_trade.
+func (b KrakenSpotExtendedBalance) Available() Decimal {
+	return b.Balance.Add(b.Credit).Sub(b.CreditUsed).Sub(b.HoldTrade)
+}
+
+type KrakenSpotExtendedBalanceResponse struct {
+	Error  []string                             `json:"error"`
+	Result map[string]KrakenSpotExtendedBalance `json:"result"`
+}
+
+// KrakenSpotTradeBalance is the margin summary of the account, in the asset
+// requested from GetTradeBalance.
+type KrakenSpotTradeBalance struct {
+	EquivalentBalance Decimal `json:"eb"`
+	TradeBalance      Decimal `json:"tb"`
+	MarginUsed        Decimal `json:"m"`
+	UnrealizedPnL     Decimal `json:"n"`
+	CostBasis         Decimal `json:"c"`
+	FloatingValuation Decimal `json:"v"`
+	Equity            Decimal `json:"e"`
+	FreeMargin        Decimal `json:"mf"`
+	// MarginLevel is a percentage, zero when there are no open positions
+	MarginLevel     Decimal `json:"ml"`
+	UnexecutedValue Decimal `json:"uv"`
+}
+
+type KrakenSpotTradeBalanceResponse struct {
+	Error  []string               `json:"error"`
+	Result KrakenSpotTradeBalance `json:"result"`
+}
+
+// KrakenSpotOrderDescription describes an order. AddOrder and EditOrder only
+// return the Order and Close summaries. Price and Price2 keep the offset and
+// percent forms the order was placed with.
+type KrakenSpotOrderDescription struct {
+	Pair      string               `json:"pair,omitempty"`
+	Side      KrakenSide           `json:"type,omitempty"`
+	OrderType KrakenOrderType      `json:"ordertype,omitempty"`
+	Price     KrakenSpotOrderPrice `json:"price"`
+	Price2    KrakenSpotOrderPrice `json:"price2"`
+	Leverage  string               `json:"leverage,omitempty"`
+	Order     string               `json:"order"`
+	Close     string               `json:"close,omitempty"`
+}
+
+type KrakenSpotAddOrderResult struct {
+	Description KrakenSpotOrderDescription `json:"descr"`
+	// TransactionIDs is empty for orders sent with Validate.
+	TransactionIDs []string `json:"txid"`
+}
+
+type KrakenSpotAddOrderResponse struct {
+	Error  []string                 `json:"error"`
+	Result KrakenSpotAddOrderResult `json:"result"`
+}
+
+type KrakenSpotAmendOrderResult struct {
+	AmendID string `json:"amend_id"`
+}
+
+type KrakenSpotAmendOrderResponse struct {
+	Error  []string                   `json:"error"`
+	Result KrakenSpotAmendOrderResult `json:"result"`
+}
+
+type KrakenSpotEditOrderResult struct {
+	Description KrakenSpotOrderDescription `json:"descr"`
+	// TransactionID is the ID of the new order.
+	TransactionID         string  `json:"txid"`
+	OriginalTransactionID string  `json:"originaltxid"`
+	Volume                Decimal `json:"volume"`
+	Price                 Decimal `json:"price"`
+	Price2                Decimal `json:"price2"`
+	OrdersCancelled       int     `json:"orders_cancelled"`
+	// Status is "ok" or "err", with the reason in ErrorMessage.
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+type KrakenSpotEditOrderResponse struct {
+	Error  []string                  `json:"error"`
+	Result KrakenSpotEditOrderResult `json:"result"`
+}
+
+type KrakenSpotCancelOrderResult struct {
+	Count int `json:"count"`
+	// Pending is set when the cancellation has been accepted but not yet
+	// processed.
+	Pending bool `json:"pending,omitempty"`
+}
+
+type KrakenSpotCancelOrderResponse struct {
+	Error  []string                    `json:"error"`
+	Result KrakenSpotCancelOrderResult `json:"result"`
+}
+
+type KrakenSpotCancelAllOrdersAfterResult struct {
+	CurrentTime KrakenTime `json:"currentTime"`
+	// TriggerTime is when open orders will be cancelled, zero once the timer
+	// is disabled.
+	TriggerTime KrakenTime `json:"triggerTime"`
+}
+
+type KrakenSpotCancelAllOrdersAfterResponse struct {
+	Error  []string                             `json:"error"`
+	Result KrakenSpotCancelAllOrdersAfterResult `json:"result"`
+}
+
+// KrakenSpotBatchOrderResult is the outcome of one order of a batch: the
+// order was placed if Error is empty.
+type KrakenSpotBatchOrderResult struct {
+	Description   KrakenSpotOrderDescription `json:"descr"`
+	TransactionID string                     `json:"txid"`
+	Error         string                     `json:"error,omitempty"`
+}
+
+// Err returns the error Kraken reported for the order as a *KrakenError, or
+// nil if the order was placed.
+func (r KrakenSpotBatchOrderResult) Err() error {
+	if len(r.Error) == 0 {
+		return nil
+	}
+	return newKrakenSpotError([]string{r.Error})
+}
+
+// KrakenSpotAddOrderBatchResult holds one result per order, in the order of
+// the request.
+type KrakenSpotAddOrderBatchResult struct {
+	Orders []KrakenSpotBatchOrderResult `json:"orders"`
+}
+
+type KrakenSpotAddOrderBatchResponse struct {
+	Error  []string                      `json:"error"`
+	Result KrakenSpotAddOrderBatchResult `json:"result"`
+}
+
+// KrakenSpotOrder is an order as returned by GetOpenOrders, GetClosedOrders
+// and QueryOrders, which key orders by transaction ID.
+type KrakenSpotOrder struct {
+	RefID         string                     `json:"refid"`
+	UserRef       int32                      `json:"userref"`
+	ClientOrderID string                     `json:"cl_ord_id"`
+	Status        KrakenSpotOrderStatus      `json:"status"`
+	Reason        string                     `json:"reason"`
+	OpenTime      KrakenTime                 `json:"opentm"`
+	StartTime     KrakenTime                 `json:"starttm"`
+	ExpireTime    KrakenTime                 `json:"expiretm"`
+	CloseTime     KrakenTime                 `json:"closetm"`
+	Description   KrakenSpotOrderDescription `json:"descr"`
+	Volume        Decimal                    `json:"vol"`
+	VolumeExec    Decimal                    `json:"vol_exec"`
+	Cost          Decimal                    `json:"cost"`
+	Fee           Decimal                    `json:"fee"`
+	// Price is the average price of the executed volume.
+	Price      Decimal                `json:"price"`
+	StopPrice  Decimal                `json:"stopprice"`
+	LimitPrice Decimal                `json:"limitprice"`
+	Trigger    KrakenSpotOrderTrigger `json:"trigger"`
+	Margin     bool                   `json:"margin"`
+	Misc       string                 `json:"misc"`
+	Flags      KrakenSpotOrderFlags   `json:"oflags"`
+	// Trades lists the IDs of the order's trades when they are requested.
+	Trades []string `json:"trades"`
+}
+
+// RemainingVolume returns the volume of the order that has not executed.
+func (o KrakenSpotOrder) RemainingVolume() Decimal {
+	return o.Volume.Sub(o.VolumeExec)
+}
+
+// IsDone reports whether the order has left the book for good.
+func (o KrakenSpotOrder) IsDone() bool {
+	return o.Status == KrakenSpotOrderClosed || o.Status == KrakenSpotOrderCanceled || o.Status == KrakenSpotOrderExpired
+}
+
+type KrakenSpotOpenOrders struct {
+	Open map[string]KrakenSpotOrder `json:"open"`
+}
+
+type KrakenSpotOpenOrdersResponse struct {
+	Error  []string             `json:"error"`
+	Result KrakenSpotOpenOrders `json:"result"`
+}
+
+type KrakenSpotClosedOrders struct {
+	Closed map[string]KrakenSpotOrder `json:"closed"`
+	// Count is the number of orders matching the filters, of which a page of
+	// up to 50 is returned.
+	Count int `json:"count"`
+}
+
+type KrakenSpotClosedOrdersResponse struct {
+	Error  []string               `json:"error"`
+	Result KrakenSpotClosedOrders `json:"result"`
+}
+
+type KrakenSpotQueryOrdersResponse struct {
+	Error  []string                   `json:"error"`
+	Result map[string]KrakenSpotOrder `json:"result"`
+}
+
+// KrakenSpotFill is a trade of the account as returned by GetTradesHistory
+// and QueryTrades, which key fills by trade transaction ID. The Closed fields
+// and Net are only set for trades that closed a margin position.
+type KrakenSpotFill struct {
+	OrderTransactionID    string          `json:"ordertxid"`
+	PositionTransactionID string          `json:"postxid"`
+	Pair                  string          `json:"pair"`
+	Time                  KrakenTime      `json:"time"`
+	Side                  KrakenSide      `json:"type"`
+	OrderType             KrakenOrderType `json:"ordertype"`
+	Price                 Decimal         `json:"price"`
+	Cost                  Decimal         `json:"cost"`
+	Fee                   Decimal         `json:"fee"`
+	Volume                Decimal         `json:"vol"`
+	Margin                Decimal         `json:"margin"`
+	Leverage              Decimal         `json:"leverage"`
+	Misc                  string          `json:"misc"`
+	TradeID               int64           `json:"trade_id"`
+	// Maker is set when the trade provided liquidity.
+	Maker          bool     `json:"maker"`
+	Ledgers        []string `json:"ledgers"`
+	PositionStatus string   `json:"posstatus"`
+	ClosedPrice    Decimal  `json:"cprice"`
+	ClosedCost     Decimal  `json:"ccost"`
+	ClosedFee      Decimal  `json:"cfee"`
+	ClosedVolume   Decimal  `json:"cvol"`
+	ClosedMargin   Decimal  `json:"cmargin"`
+	Net            Decimal  `json:"net"`
+	Trades         []string `json:"trades"`
+}
+
+type KrakenSpotTradesHistory struct {
+	Trades map[string]KrakenSpotFill `json:"trades"`
+	// Count is the number of trades matching the filters, of which a page of
+	// up to 50 is returned.
+	Count int `json:"count"`
+}
+
+type KrakenSpotTradesHistoryResponse struct {
+	Error  []string                `json:"error"`
+	Result KrakenSpotTradesHistory `json:"result"`
+}
+
+type KrakenSpotQueryTradesResponse struct {
+	Error  []string                  `json:"error"`
+	Result map[string]KrakenSpotFill `json:"result"`
+}
+
+// KrakenSpotPosition is an open margin position, keyed by position
+// transaction ID in the result of GetOpenPositions. Value and Net are only
+// set when the positions are requested with calculations.
+type KrakenSpotPosition struct {
+	OrderTransactionID string               `json:"ordertxid"`
+	PositionStatus     string               `json:"posstatus"`
+	Pair               string               `json:"pair"`
+	Time               KrakenTime           `json:"time"`
+	Side               KrakenSide           `json:"type"`
+	OrderType          KrakenOrderType      `json:"ordertype"`
+	Cost               Decimal              `json:"cost"`
+	Fee                Decimal              `json:"fee"`
+	Volume             Decimal              `json:"vol"`
+	VolumeClosed       Decimal              `json:"vol_closed"`
+	Margin             Decimal              `json:"margin"`
+	Value              Decimal              `json:"value"`
+	Net                Decimal              `json:"net"`
+	Terms              string               `json:"terms"`
+	RolloverTime       KrakenTime           `json:"rollovertm"`
+	Misc               string               `json:"misc"`
+	Flags              KrakenSpotOrderFlags `json:"oflags"`
+}
+
+type KrakenSpotOpenPositionsResponse struct {
+	Error  []string                      `json:"error"`
+	Result map[string]KrakenSpotPosition `json:"result"`
+}
+
+// KrakenSpotLedgerEntry is a change to the balance of an asset, keyed by
+// ledger ID in the results of GetLedgers and QueryLedgers.
+type KrakenSpotLedgerEntry struct {
+	// RefID is the ID of the trade, deposit or withdrawal behind the entry.
+	RefID      string               `json:"refid"`
+	Time       KrakenTime           `json:"time"`
+	Type       KrakenSpotLedgerType `json:"type"`
+	SubType    string               `json:"subtype"`
+	AssetClass KrakenAssetClass     `json:"aclass"`
+	Asset      string               `json:"asset"`
+	Amount     Decimal              `json:"amount"`
+	Fee        Decimal              `json:"fee"`
+	// Balance is the balance of the asset after the entry.
+	Balance Decimal `json:"balance"`
+}
+
+// Net returns the change to the balance: the amount less the fee.
+func (e KrakenSpotLedgerEntry) Net() Decimal {
+	return e.Amount.Sub(e.Fee)
+}
+
+type KrakenSpotLedgers struct {
+	Ledger map[string]KrakenSpotLedgerEntry `json:"ledger"`
+	// Count is the number of entries matching the filters, of which a page of
+	// up to 50 is returned.
+	Count int `json:"count"`
+}
+
+type KrakenSpotLedgersResponse struct {
+	Error  []string          `json:"error"`
+	Result KrakenSpotLedgers `json:"result"`
+}
+
+type KrakenSpotQueryLedgersResponse struct {
+	Error  []string                         `json:"error"`
+	Result map[string]KrakenSpotLedgerEntry `json:"result"`
+}
+
+// KrakenSpotFeeInfo is the fee the account pays on a pair. The Next fields
+// are zero at the last tier.
+type KrakenSpotFeeInfo struct {
+	// Fee is the current fee in percent.
+	Fee        Decimal `json:"fee"`
+	MinFee     Decimal `json:"minfee"`
+	MaxFee     Decimal `json:"maxfee"`
+	NextFee    Decimal `json:"nextfee"`
+	NextVolume Decimal `json:"nextvolume"`
+	TierVolume Decimal `json:"tiervolume"`
+}
+
+// KrakenSpotTradeVolume is the 30 day trading volume of the account and the
+// taker and maker fees it pays on the requested pairs, keyed by pair name.
+type KrakenSpotTradeVolume struct {
+	Currency  string                       `json:"currency"`
+	Volume    Decimal                      `json:"volume"`
+	Fees      map[string]KrakenSpotFeeInfo `json:"fees"`
+	FeesMaker map[string]KrakenSpotFeeInfo `json:"fees_maker"`
+}
+
+type KrakenSpotTradeVolumeResponse struct {
+	Error  []string              `json:"error"`
+	Result KrakenSpotTradeVolume `json:"result"`
+}
@@ -0,0 +1,204 @@
+package kraken
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KrakenTime is a timestamp decoded from any of the forms Kraken uses: Unix
+// seconds as a JSON number or string, with or without a fractional part
+// (1688669597.8277369), or an RFC 3339 / ISO 8601 string
+// ("2023-07-06T18:25:33.102Z"). Fractional seconds are decoded exactly, down
+// to the nanosecond, without going through float64. null, "" and 0, which
+// Kraken uses for unset times such as the expiry of an order without one,
+// decode to the zero time.
+type KrakenTime struct {
+	time.Time
+}
+
+// UnixSeconds formats t as Unix seconds with as many fractional digits as
+// needed, the form Kraken uses for trade times.
+func (t KrakenTime) UnixSeconds() string {
+	return formatUnixSeconds(t.Time)
+}
+
+func (t *KrakenTime) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*t = KrakenTime{}
+		return nil
+	}
+
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := parseKrakenTime(text)
+	if err != nil {
+		return err
+	}
+	*t = KrakenTime{parsed}
+	return nil
+}
+
+// MarshalJSON encodes t as an RFC 3339 string with nanoseconds, or null for
+// the zero time.
+func (t KrakenTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.UTC().Format(time.RFC3339Nano))
+}
+
+func parseKrakenTime(text string) (time.Time, error) {
+	if len(text) == 0 {
+		return time.Time{}, nil
+	}
+	if strings.ContainsAny(text, "-:T") && !strings.HasPrefix(text, "-") {
+		return time.Parse(time.RFC3339Nano, text)
+	}
+	t, err := parseUnixSeconds(text)
+	if err != nil || t.UnixNano() == 0 {
+		return time.Time{}, err
+	}
+	return t, nil
+}
+
+// parseUnixSeconds parses decimal Unix seconds exactly. Digits beyond the
+// nanosecond are truncated.
+func parseUnixSeconds(text string) (time.Time, error) {
+	intPart, fracPart, _ := strings.Cut(text, ".")
+	seconds, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("kraken: invalid unix time %q", text)
+	}
+
+	var nanos int64
+	if len(fracPart) > 0 {
+		if len(fracPart) > 9 {
+			fracPart = fracPart[:9]
+		}
+		nanos, err = strconv.ParseInt(fracPart+strings.Repeat("0", 9-len(fracPart)), 10, 64)
+		if err != nil || nanos < 0 {
+			return time.Time{}, fmt.Errorf("kraken: invalid unix time %q", text)
+		}
+		if strings.HasPrefix(intPart, "-") {
+			nanos = -nanos
+		}
+	}
+	return time.Unix(seconds, nanos).UTC(), nil
+}
+
+func formatUnixSeconds(t time.Time) string {
+	nanos := t.UnixNano()
+	seconds, frac := nanos/int64(time.Second), nanos%int64(time.Second)
+	if frac == 0 {
+		return strconv.FormatInt(seconds, 10)
+	}
+
+	sign := ""
+	if frac < 0 {
+		frac = -frac
+		if seconds == 0 {
+			sign = "-"
+		}
+	}
+	digits := strings.TrimRight(fmt.Sprintf("%09d", frac), "0")
+	return sign + strconv.FormatInt(seconds, 10) + "." + digits
+}
+
+// krakenCursorNanosThreshold separates cursors in nanoseconds from cursors in
+// seconds: 1e14 seconds is millions of years away, 1e14 nanoseconds is a day
+// after the epoch.
+const krakenCursorNanosThreshold = 1e14
+
+// KrakenCursor is a pagination cursor, such as the "last" value returned by
+// the Trades (Unix nanoseconds), OHLC and Spread (Unix seconds) endpoints. It
+// keeps the unit Kraken used so String returns exactly the value to send back
+// as since.
+type KrakenCursor struct {
+	time  time.Time
+	nanos bool
+}
+
+// NewKrakenCursor returns a cursor at t, expressed in nanoseconds if nanos is
+// set and in whole seconds otherwise.
+func NewKrakenCursor(t time.Time, nanos bool) KrakenCursor {
+	if !nanos {
+		t = t.Truncate(time.Second)
+	}
+	return KrakenCursor{time: t.UTC(), nanos: nanos}
+}
+
+// ParseKrakenCursor parses a cursor in Unix seconds or nanoseconds, telling
+// them apart by magnitude.
+func ParseKrakenCursor(s string) (KrakenCursor, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return KrakenCursor{}, fmt.Errorf("kraken: invalid cursor %q", s)
+	}
+	if n >= krakenCursorNanosThreshold {
+		return KrakenCursor{time: time.Unix(0, n).UTC(), nanos: true}, nil
+	}
+	return KrakenCursor{time: time.Unix(n, 0).UTC()}, nil
+}
+
+func (c KrakenCursor) Time() time.Time {
+	return c.time
+}
+
+func (c KrakenCursor) IsZero() bool {
+	return c.time.IsZero()
+}
+
+// String returns the cursor as Kraken sent it, or "" for the zero cursor.
+func (c KrakenCursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	if c.nanos {
+		return strconv.FormatInt(c.time.UnixNano(), 10)
+	}
+	return strconv.FormatInt(c.time.Unix(), 10)
+}
+
+func (c *KrakenCursor) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*c = KrakenCursor{}
+		return nil
+	}
+
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseKrakenCursor(text)
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// MarshalJSON encodes the cursor in Kraken's form: a string of nanoseconds or
+// a number of seconds.
+func (c KrakenCursor) MarshalJSON() ([]byte, error) {
+	if c.IsZero() {
+		return []byte("null"), nil
+	}
+	if c.nanos {
+		return json.Marshal(c.String())
+	}
+	return []byte(c.String()), nil
+}